@@ -39,7 +39,7 @@
           executable = true;
           text = ''
             #!/usr/bin/env bash
-            exec ${lib.getExe cfg.package} hooks pre-push
+            exec ${lib.getExe cfg.package} hooks pre-push "$@"
           '';
         };
       };
@@ -13,6 +13,11 @@
               linters = ["revive"];
               text = "package-directory-mismatch";
             }
+            {
+              path = "cmd/handler/shared";
+              linters = ["revive"];
+              text = "package-directory-mismatch";
+            }
             {
               path = "internal/git/config";
               linters = ["revive"];
@@ -29,7 +34,7 @@
               text = "Use of unsafe calls should be audited";
             }
             {
-              path = "cmd/handler/hooks/post-commit";
+              path = "cmd/handler/shared/fake-date";
               linters = ["gosec"];
               text = "G404: Use of weak random number generator";
             }
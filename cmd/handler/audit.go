@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/krostar/cli"
+
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
+	"github.com/krostar/git-workhours/internal/audit"
+	"github.com/krostar/git-workhours/internal/git"
+)
+
+// Audit returns the command listing the commits whose dates have been rewritten.
+func Audit() cli.Command { return new(cmdAudit) }
+
+type cmdAudit struct {
+	cfg cmdAuditConfig
+}
+
+type cmdAuditConfig struct {
+	handlershared.Config `env:"^"`
+
+	All    bool
+	Commit string
+	Since  string
+	Until  string
+	Output string
+}
+
+func (cfg *cmdAuditConfig) SetDefault() {
+	cfg.Output = "text"
+}
+
+func (*cmdAudit) Description() string {
+	return "List the commits whose dates have been rewritten, with their real dates, from the audit log."
+}
+
+func (cmd *cmdAudit) Flags() []cli.Flag {
+	return []cli.Flag{
+		cli.NewBuiltinFlag("audit-log", "", &cmd.cfg.AuditLog, "Path of the audit log, defaults to $XDG_STATE_HOME/git-workhours/audit.jsonl"),
+		cli.NewBuiltinFlag("all", "a", &cmd.cfg.All, "List rewritten commits of all repositories instead of the current one"),
+		cli.NewBuiltinFlag("commit", "", &cmd.cfg.Commit, "Only list the rewrite of the commit with this original or new hash prefix"),
+		cli.NewBuiltinFlag("since", "", &cmd.cfg.Since, "Only list commits really authored after this date, in any format git understands, eg: '2026-10-01', '2 weeks ago'"),
+		cli.NewBuiltinFlag("until", "", &cmd.cfg.Until, "Only list commits really authored before this date, in any format git understands"),
+		cli.NewBuiltinFlag("output", "o", &cmd.cfg.Output, "Output format (text, json)"),
+	}
+}
+
+func (cmd *cmdAudit) Hook() *cli.Hook {
+	return &cli.Hook{BeforeCommandExecution: handlershared.SourceConfigHook(&cmd.cfg)}
+}
+
+func (cmd *cmdAudit) Execute(ctx context.Context, _, _ []string) error {
+	if cmd.cfg.Output != "text" && cmd.cfg.Output != "json" {
+		return cli.NewErrorWithHelp(fmt.Errorf("unknown output format %q", cmd.cfg.Output))
+	}
+
+	path, err := cmd.cfg.AuditLogPath()
+	if err != nil {
+		return fmt.Errorf("unable to find audit log: %w", err)
+	}
+
+	if path == "" {
+		return errors.New("audit log is disabled, wh.auditlog is set to 'off'")
+	}
+
+	filter, err := cmd.filter(ctx)
+	if err != nil {
+		return err
+	}
+
+	entries, err := audit.Read(path)
+	if err != nil {
+		return err
+	}
+
+	entries = filter.Apply(entries)
+
+	if cmd.cfg.Output == "json" {
+		if entries == nil {
+			entries = []audit.Entry{}
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(entries)
+	}
+
+	return printAuditEntries(entries, cmd.cfg.All)
+}
+
+func (cmd *cmdAudit) filter(ctx context.Context) (audit.Filter, error) {
+	filter := audit.Filter{Commit: cmd.cfg.Commit}
+
+	if !cmd.cfg.All {
+		repository, err := git.RepositoryPath(ctx)
+		if err != nil {
+			return filter, fmt.Errorf("not in a git repository, use --all to list rewritten commits of all repositories: %w", err)
+		}
+
+		filter.Repository = repository
+	}
+
+	if cmd.cfg.Since != "" {
+		since, err := git.ResolveDate(ctx, cmd.cfg.Since)
+		if err != nil {
+			return filter, fmt.Errorf("could not resolve since date: %w", err)
+		}
+
+		filter.Since = since
+	}
+
+	if cmd.cfg.Until != "" {
+		until, err := git.ResolveDate(ctx, cmd.cfg.Until)
+		if err != nil {
+			return filter, fmt.Errorf("could not resolve until date: %w", err)
+		}
+
+		filter.Until = until
+	}
+
+	return filter, nil
+}
+
+// printAuditEntries prints a table of the rewritten commits, oldest rewrite first.
+func printAuditEntries(entries []audit.Entry, withRepository bool) error {
+	const layout = "2006-01-02 15:04:05 -0700"
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	if withRepository {
+		fmt.Fprint(w, "REPOSITORY\t")
+	}
+
+	fmt.Fprintln(w, "COMMIT\tNEW COMMIT\tAUTHOR DATE\tFAKED AUTHOR DATE\tREWRITTEN BY")
+
+	for _, entry := range entries {
+		if withRepository {
+			fmt.Fprintf(w, "%s\t", entry.Repository)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			entry.OldHash[:min(len(entry.OldHash), 12)], entry.NewHash[:min(len(entry.NewHash), 12)],
+			entry.AuthorDate.Format(layout), entry.FakedAuthorDate.Format(layout),
+			entry.Origin,
+		)
+	}
+
+	return w.Flush()
+}
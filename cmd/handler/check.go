@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/krostar/cli"
+	clidi "github.com/krostar/cli/di"
+
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
+	"github.com/krostar/git-workhours/internal/git"
+)
+
+// Check returns the command checking commits of a revision range against the work schedule.
+func Check() cli.Command { return new(cmdCheck) }
+
+type cmdCheck struct {
+	cfg    handlershared.Config
+	logger *slog.Logger
+}
+
+func (*cmdCheck) Description() string {
+	return "Report commits of a revision range whose author or committer date is outside of work hours, useful in CI."
+}
+
+func (*cmdCheck) Usage() string {
+	return "<revision range>"
+}
+
+func (cmd *cmdCheck) Flags() []cli.Flag {
+	return handlershared.Flags(&cmd.cfg)
+}
+
+func (cmd *cmdCheck) Hook() *cli.Hook {
+	return &cli.Hook{
+		BeforeCommandExecution: func(ctx context.Context) error {
+			if err := clidi.Invoke(ctx, func(logger *slog.Logger) { cmd.logger = logger.With("cmd", "check") }); err != nil {
+				return err
+			}
+
+			return handlershared.SourceConfigHook(&cmd.cfg)(ctx)
+		},
+	}
+}
+
+func (cmd *cmdCheck) Execute(ctx context.Context, args, _ []string) error {
+	if len(args) != 1 {
+		return cli.NewErrorWithHelp(fmt.Errorf("expected a single revision range, got %d arguments", len(args)))
+	}
+
+	commits, err := git.ListCommits(ctx, "--reverse", "--topo-order", args[0], "--")
+	if err != nil {
+		return fmt.Errorf("unable to list commits of %s: %w", args[0], err)
+	}
+
+	groups, err := cmd.cfg.GroupByAuthor(ctx, "", commits)
+	if err != nil {
+		return err
+	}
+
+	var (
+		overtime []handlershared.OvertimeCommit
+		rejected int
+	)
+
+	for _, group := range groups {
+		schedule, err := group.Config.WorkSchedule()
+		if err != nil {
+			return fmt.Errorf("unable to parse schedule: %w", err)
+		}
+
+		groupOvertime := handlershared.FindOvertimeCommits(schedule, group.Commits)
+		if !group.Config.AllowOvertime {
+			rejected += len(groupOvertime)
+		}
+
+		overtime = append(overtime, groupOvertime...)
+	}
+
+	if len(overtime) == 0 {
+		cmd.logger.InfoContext(ctx, "all commits are within work hours", "commits", len(commits))
+		return nil
+	}
+
+	// commits of the different authors are listed in the order of the revision range
+	positions := make(map[string]int, len(commits))
+	for i, commit := range commits {
+		positions[commit.Hash] = i
+	}
+
+	slices.SortFunc(overtime, func(a, b handlershared.OvertimeCommit) int {
+		return cmp.Compare(positions[a.Commit.Hash], positions[b.Commit.Hash])
+	})
+
+	fmt.Printf("%d out of %d commits were made outside of work hours:\n%s\n", len(overtime), len(commits), handlershared.FormatOvertimeCommits(overtime))
+
+	if rejected > 0 {
+		return cli.NewErrorWithExitStatus(fmt.Errorf("%d commits were made outside of work hours", rejected), 3)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
+	"github.com/krostar/git-workhours/internal/audit"
+	"github.com/krostar/git-workhours/internal/git"
+)
+
+// realCommitsFilter selects commits by author email and by real author date, zero fields don't filter anything.
+type realCommitsFilter struct {
+	authors []string
+	// since and until are dates in any format git understands.
+	since string
+	until string
+}
+
+// listRealCommits lists the commits of the revision range selected by the filter, with their real author dates.
+// Real dates of rewritten commits are looked up in the audit log.
+func listRealCommits(ctx context.Context, logger *slog.Logger, cfg handlershared.Config, revisionRange string, filter realCommitsFilter) ([]git.Commit, error) {
+	var since, until time.Time
+
+	for _, bound := range []struct {
+		raw  string
+		dest *time.Time
+	}{{raw: filter.since, dest: &since}, {raw: filter.until, dest: &until}} {
+		if bound.raw == "" {
+			continue
+		}
+
+		date, err := git.ResolveDate(ctx, bound.raw)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve date: %w", err)
+		}
+
+		*bound.dest = date
+	}
+
+	realDates, err := realAuthorDates(logger, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := git.ListCommits(ctx, revisionRange, "--")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list commits of %s: %w", revisionRange, err)
+	}
+
+	var selected []git.Commit
+
+	for _, commit := range commits {
+		if len(filter.authors) > 0 && !slices.ContainsFunc(filter.authors, func(author string) bool { return strings.EqualFold(author, commit.AuthorEmail) }) {
+			continue
+		}
+
+		if real, found := realDates[commit.Hash]; found {
+			commit.AuthorDate = real
+		}
+
+		if (!since.IsZero() && commit.AuthorDate.Before(since)) || (!until.IsZero() && commit.AuthorDate.After(until)) {
+			continue
+		}
+
+		selected = append(selected, commit)
+	}
+
+	return selected, nil
+}
+
+// realAuthorDates returns the real author dates of rewritten commits recorded in the audit log, if enabled.
+func realAuthorDates(logger *slog.Logger, cfg handlershared.Config) (map[string]time.Time, error) {
+	path, err := cfg.AuditLogPath()
+	if err != nil || path == "" {
+		return nil, err
+	}
+
+	entries, err := audit.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("using real dates of rewritten commits from audit log", "path", path, "entries", len(entries))
+
+	return audit.RealAuthorDates(entries), nil
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/krostar/cli"
+
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
+	"github.com/krostar/git-workhours/internal/git"
+)
+
+// Doctor returns the command diagnosing the git-workhours setup.
+func Doctor() cli.Command { return new(cmdDoctor) }
+
+type cmdDoctor struct {
+	cfg cmdDoctorConfig
+}
+
+type cmdDoctorConfig struct {
+	handlershared.Config `env:"^"`
+
+	FakeValidTime bool
+	Hooks         []string
+	Output        string
+}
+
+func (cfg *cmdDoctorConfig) SetDefault() {
+	cfg.Output = "text"
+}
+
+func (*cmdDoctor) Description() string {
+	return "Check whether git-workhours is correctly installed and configured."
+}
+
+func (cmd *cmdDoctor) Flags() []cli.Flag {
+	return append(handlershared.Flags(&cmd.cfg.Config),
+		cli.NewBuiltinFlag("fake-valid-time", "", &cmd.cfg.FakeValidTime, "Automatically adjust commit times to fall within work hours"),
+		cli.NewBuiltinSliceFlag("hooks", "", &cmd.cfg.Hooks, "Hooks expected to be installed, eg: 'pre-commit,post-commit,pre-push'"),
+		cli.NewBuiltinFlag("output", "o", &cmd.cfg.Output, "Output format (text, json)"),
+	)
+}
+
+func (cmd *cmdDoctor) Hook() *cli.Hook {
+	return &cli.Hook{BeforeCommandExecution: handlershared.SourceConfigHook(&cmd.cfg)}
+}
+
+// doctorCheck is the result of a single diagnostic.
+type doctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+const (
+	doctorCheckPass = "pass"
+	doctorCheckFail = "fail"
+)
+
+func (cmd *cmdDoctor) Execute(ctx context.Context, _, _ []string) error {
+	if cmd.cfg.Output != "text" && cmd.cfg.Output != "json" {
+		return cli.NewErrorWithHelp(fmt.Errorf("unknown output format %q", cmd.cfg.Output))
+	}
+
+	var checks []doctorCheck
+
+	checks = append(checks, cmd.checkGit(ctx))
+	if checks[0].Status == doctorCheckPass {
+		checks = append(checks, cmd.checkHooks(ctx)...)
+	}
+
+	checks = append(checks, cmd.checkConfig()...)
+	checks = append(checks, cmd.checkRecursionGuard())
+
+	if err := printDoctorChecks(checks, cmd.cfg.Output); err != nil {
+		return fmt.Errorf("unable to print checks: %w", err)
+	}
+
+	var failed int
+
+	for _, check := range checks {
+		if check.Status == doctorCheckFail {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return cli.NewErrorWithExitStatus(fmt.Errorf("%d checks failed", failed), 1)
+	}
+
+	return nil
+}
+
+func (*cmdDoctor) checkGit(ctx context.Context) doctorCheck {
+	check := doctorCheck{Name: "git"}
+
+	path, err := exec.LookPath("git")
+	if err != nil {
+		check.Status, check.Message = doctorCheckFail, "git is not reachable: "+err.Error()
+		return check
+	}
+
+	version, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		check.Status, check.Message = doctorCheckFail, fmt.Sprintf("unable to run %s: %v", path, err)
+		return check
+	}
+
+	check.Status, check.Message = doctorCheckPass, fmt.Sprintf("%s (%s)", strings.TrimSpace(string(version)), path)
+
+	return check
+}
+
+func (cmd *cmdDoctor) checkHooks(ctx context.Context) []doctorCheck {
+	hooksDir, err := git.HooksDir(ctx)
+	if err != nil {
+		return []doctorCheck{{Name: "hooks directory", Status: doctorCheckFail, Message: "not in a git repository, or unable to find hooks directory: " + err.Error()}}
+	}
+
+	checks := []doctorCheck{cmd.checkHooksPath(ctx, hooksDir)}
+
+	for _, hook := range hooksOrDefault(cmd.cfg.Hooks) {
+		check := doctorCheck{Name: "hook " + hook}
+		path := filepath.Join(hooksDir, hook)
+
+		content, err := os.ReadFile(path)
+		info, statErr := os.Stat(path)
+
+		switch {
+		case err != nil || statErr != nil:
+			check.Status, check.Message = doctorCheckFail, path+" does not exist, run 'git-workhours install'"
+		case !strings.Contains(string(content), "git-workhours"):
+			check.Status, check.Message = doctorCheckFail, path+" does not run git-workhours"
+		case info.Mode().Perm()&0o111 == 0:
+			check.Status, check.Message = doctorCheckFail, path+" is not executable, run 'chmod +x "+path+"'"
+		default:
+			check.Status, check.Message = doctorCheckPass, path+" is installed"
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+func (*cmdDoctor) checkHooksPath(ctx context.Context, hooksDir string) doctorCheck {
+	check := doctorCheck{Name: "core.hooksPath"}
+
+	hooksPath, err := git.GetConfigPath(ctx, git.ConfigScopeAll, "core.hooksPath")
+	if err != nil {
+		check.Status, check.Message = doctorCheckFail, err.Error()
+		return check
+	}
+
+	if hooksPath == "" {
+		check.Status, check.Message = doctorCheckPass, "not set, hooks are run from "+hooksDir
+		return check
+	}
+
+	check.Status, check.Message = doctorCheckPass, "hooks are run from "+hooksDir+", the repository's own hooks are ignored by git unless wh.runlocalhook is set"
+
+	return check
+}
+
+func (cmd *cmdDoctor) checkConfig() []doctorCheck {
+	checks := []doctorCheck{{Name: "schedule", Status: doctorCheckPass, Message: "schedule is valid"}}
+
+	if cmd.cfg.Schedule == "" && cmd.cfg.ScheduleFile == "" {
+		checks[0].Status, checks[0].Message = doctorCheckFail, "no schedule is set, set wh.schedule or wh.schedulefile"
+	} else if _, err := cmd.cfg.WorkSchedule(); err != nil {
+		checks[0].Status, checks[0].Message = doctorCheckFail, err.Error()
+	}
+
+	mask := doctorCheck{Name: "mask timezone", Status: doctorCheckPass, Message: "timezone mask is valid"}
+	if _, err := cmd.cfg.MaskLocation(); err != nil {
+		mask.Status, mask.Message = doctorCheckFail, err.Error()
+	} else if cmd.cfg.MaskTimezone == "" {
+		mask.Message = "not set"
+	}
+
+	checks = append(checks, mask)
+
+	overtime := doctorCheck{Name: "overtime", Status: doctorCheckPass}
+
+	switch {
+	case cmd.cfg.FakeValidTime && !cmd.cfg.AllowOvertime:
+		overtime.Status, overtime.Message = doctorCheckFail, "fakevalidtime is set but allowovertime is not, commits made over time are rejected instead of being moved"
+	case cmd.cfg.FakeValidTime:
+		overtime.Message = "commits made over time are moved within work hours"
+	case cmd.cfg.AllowOvertime:
+		overtime.Message = "commits made over time are allowed with a warning, set fakevalidtime to move them within work hours"
+	default:
+		overtime.Message = "commits made over time are rejected"
+	}
+
+	return append(checks, overtime)
+}
+
+func (*cmdDoctor) checkRecursionGuard() doctorCheck {
+	check := doctorCheck{Name: "GIT_WH_ONGOING", Status: doctorCheckPass, Message: "not set"}
+
+	if os.Getenv("GIT_WH_ONGOING") != "" {
+		check.Status, check.Message = doctorCheckFail, "GIT_WH_ONGOING is set in the environment, hooks are skipped, unset it"
+	}
+
+	return check
+}
+
+func printDoctorChecks(checks []doctorCheck, output string) error {
+	if output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(checks)
+	}
+
+	for _, check := range checks {
+		fmt.Printf("[%s] %s: %s\n", strings.ToUpper(check.Status), check.Name, check.Message)
+	}
+
+	return nil
+}
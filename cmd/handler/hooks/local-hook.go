@@ -0,0 +1,131 @@
+package handlerhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/krostar/cli"
+	"github.com/mattn/go-isatty"
+
+	"github.com/krostar/git-workhours/internal/git"
+)
+
+// localHook runs the repository's own hook after git-workhours ones,
+// as git doesn't run them when core.hooksPath is set.
+type localHook struct {
+	enabled bool
+}
+
+// wrap runs execute, then the repository's hook named after the provided one, with the same arguments and standard input.
+// The repository's hook is only run if execute succeeds, its exit status is forwarded to git.
+func (lh *localHook) wrap(ctx context.Context, logger *slog.Logger, name string, args []string, execute func() error) error {
+	if !lh.enabled {
+		return execute()
+	}
+
+	path, err := lh.path(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		logger.DebugContext(ctx, "no local hook to run", "hook", name)
+		return execute()
+	}
+
+	stdin, restore, err := lh.bufferStdin()
+	if err != nil {
+		return fmt.Errorf("unable to buffer standard input: %w", err)
+	}
+
+	err = execute()
+	restore()
+
+	if err != nil {
+		return err
+	}
+
+	logger.DebugContext(ctx, "running local hook", "hook", name, "path", path)
+
+	localCmd := exec.CommandContext(ctx, path, args...) //nolint:gosec // running the repository's hook is the purpose
+	localCmd.Stdin = bytes.NewReader(stdin)
+	localCmd.Stdout = os.Stdout
+	localCmd.Stderr = os.Stderr
+
+	if err := localCmd.Run(); err != nil {
+		if exitErr := (*exec.ExitError)(nil); errors.As(err, &exitErr) {
+			return cli.NewErrorWithExitStatus(fmt.Errorf("local %s hook failed: %w", name, err), uint8(exitErr.ExitCode())) //nolint:gosec // exit codes fit in a byte
+		}
+
+		return fmt.Errorf("unable to run local %s hook: %w", name, err)
+	}
+
+	return nil
+}
+
+// path returns the path of the repository's executable hook, or an empty string if there is none to run.
+// The repository's hook is not run if git already runs hooks from the repository, as it would be the one running git-workhours.
+func (*localHook) path(ctx context.Context, name string) (string, error) {
+	gitDir, err := git.CommonDir(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	hooksDir, err := git.HooksDir(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	localHooksDir := filepath.Join(gitDir, "hooks")
+	if filepath.Clean(hooksDir) == filepath.Clean(localHooksDir) {
+		return "", nil
+	}
+
+	path := filepath.Join(localHooksDir, name)
+
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Mode().Perm()&0o111 == 0 {
+		return "", nil //nolint:nilerr // missing or non-executable hooks are not run by git either
+	}
+
+	return path, nil
+}
+
+// bufferStdin reads the whole standard input, and replaces it with a copy of it so that it can be read again.
+// The returned function restores the original standard input.
+func (*localHook) bufferStdin() ([]byte, func(), error) {
+	original := os.Stdin
+
+	if isatty.IsTerminal(original.Fd()) {
+		return nil, func() {}, nil
+	}
+
+	content, err := io.ReadAll(original)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go func() {
+		_, _ = w.Write(content)
+		_ = w.Close()
+	}()
+
+	os.Stdin = r
+
+	return content, func() {
+		os.Stdin = original
+		_ = r.Close()
+	}, nil
+}
@@ -0,0 +1,103 @@
+package handlerhooks
+
+import (
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/krostar/cli"
+	sourceenv "github.com/krostar/cli/cfg/source/env"
+
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
+	gitconfig "github.com/krostar/git-workhours/internal/git/config"
+)
+
+// configField describes a resolved configuration field, and which source set it.
+type configField struct {
+	Name   string
+	Value  any
+	Origin string
+}
+
+// configFields lists the fields of the configuration, with the source that set each of them,
+// following the sources precedence: defaults, then git configuration, environment, and flags.
+// Embedded shared configuration is skipped unless includeShared is set.
+func configFields(cfg any, flags []cli.Flag, gitOrigins []gitconfig.Origin, includeShared bool) []configField {
+	setByFlags := make(map[uintptr]string)
+
+	for _, flag := range flags {
+		if flag.IsSet() {
+			setByFlags[uintptr(reflect.ValueOf(flag.Destination()).UnsafePointer())] = "flag --" + strings.TrimPrefix(flag.LongName(), "--")
+		}
+	}
+
+	var fields []configField
+
+	var walk func(v reflect.Value, envPrefix string)
+
+	walk = func(v reflect.Value, envPrefix string) {
+		t := v.Type()
+
+		for i := range t.NumField() {
+			tfield, vfield := t.Field(i), v.Field(i)
+			tag := tfield.Tag.Get("env")
+
+			if tfield.Anonymous && tfield.Type.Kind() == reflect.Struct {
+				if tfield.Type == reflect.TypeFor[handlershared.Config]() && !includeShared {
+					continue
+				}
+
+				if tag == "^" || tag == "-" {
+					walk(vfield, envPrefix)
+				} else {
+					walk(vfield, envPrefix+"_"+strings.ToUpper(tfield.Name))
+				}
+
+				continue
+			}
+
+			if !tfield.IsExported() {
+				continue
+			}
+
+			field := configField{Name: tfield.Name, Value: vfield.Interface(), Origin: "default"}
+
+			for _, origin := range gitOrigins {
+				if key := strings.ToLower(origin.Key); key == strings.ToLower(tfield.Name) || strings.HasPrefix(key, strings.ToLower(tfield.Name)+".") {
+					field.Origin = "git config " + origin.String()
+				}
+			}
+
+			if tag != "-" {
+				for _, name := range append(strings.Split(tag, ","), envPrefix+"_"+strings.ToUpper(tfield.Name)) {
+					if name = strings.TrimSpace(name); name == "" || name == "^" {
+						continue
+					}
+
+					name = sourceenv.SanitizeName(name)
+					if value, found := os.LookupEnv(name); found && value != "" {
+						field.Origin = "env " + name
+						break
+					}
+				}
+			}
+
+			if vfield.CanAddr() {
+				if flag, found := setByFlags[uintptr(vfield.Addr().UnsafePointer())]; found {
+					field.Origin = flag
+				}
+			}
+
+			fields = append(fields, field)
+		}
+	}
+
+	v := reflect.ValueOf(cfg)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	walk(v, "WH")
+
+	return fields
+}
@@ -5,12 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"math/rand/v2"
 	"time"
 
 	"github.com/krostar/cli"
 	clidi "github.com/krostar/cli/di"
 
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
 	"github.com/krostar/git-workhours/internal/git"
 	"github.com/krostar/git-workhours/internal/workhours"
 )
@@ -19,17 +19,19 @@ import (
 func PostCommit() cli.Command { return new(cmdPostCommit) }
 
 type cmdPostCommit struct {
-	cfg    cmdPostCommitConfig
-	logger *slog.Logger
+	cfg       cmdPostCommitConfig
+	logger    *slog.Logger
+	localHook *localHook
 }
 
 type cmdPostCommitConfig struct {
-	hookSharedConfig `env:"-"`
+	handlershared.Config `env:"-"`
 
-	AuthorDate    string `env:"GIT_AUTHOR_DATE"`
-	FakeValidTime bool
-	Force         bool
-	DryRun        bool
+	AuthorDate          string `env:"GIT_AUTHOR_DATE"`
+	FakeValidTime       bool
+	UseScheduleTimezone bool
+	Force               bool
+	DryRun              bool
 }
 
 func (*cmdPostCommit) Description() string {
@@ -42,6 +44,7 @@ func (cmd *cmdPostCommit) Flags() []cli.Flag {
 		cli.NewBuiltinFlag("dry-run", "", &cmd.cfg.DryRun, "Don't perform any writing operations"),
 		cli.NewBuiltinFlag("author-date", "", &cmd.cfg.AuthorDate, "Date of the commit"),
 		cli.NewBuiltinFlag("fake-valid-time", "", &cmd.cfg.FakeValidTime, "Automatically adjust commit times to fall within work hours"),
+		cli.NewBuiltinFlag("use-schedule-timezone", "", &cmd.cfg.UseScheduleTimezone, "Adjusted commit times carry the schedule timezone offset instead of the original one"),
 	}
 }
 
@@ -49,17 +52,36 @@ func (cmd *cmdPostCommit) Hook() *cli.Hook {
 	return &cli.Hook{
 		BeforeCommandExecution: func(ctx context.Context) error {
 			return errors.Join(
-				clidi.Invoke(ctx, func(shared *hookSharedConfig, logger *slog.Logger) {
+				handlershared.SourceConfigHook(&cmd.cfg)(ctx),
+				clidi.Invoke(ctx, func(shared *handlershared.Config, logger *slog.Logger, localHook *localHook) {
+					cmd.localHook = localHook
 					cmd.logger = logger.With("hook", "post-commit")
-					cmd.cfg.hookSharedConfig = *shared
+					cmd.cfg.Config = *shared
 				}),
-				sourceConfigHook(&cmd.cfg)(ctx),
 			)
 		},
 	}
 }
 
-func (cmd *cmdPostCommit) Execute(ctx context.Context, _, _ []string) error {
+func (cmd *cmdPostCommit) Execute(ctx context.Context, args, _ []string) error {
+	return cmd.localHook.wrap(ctx, cmd.logger, "post-commit", args, func() error { return cmd.execute(ctx) })
+}
+
+func (cmd *cmdPostCommit) execute(ctx context.Context) error {
+	unrestricted, email, err := cmd.cfg.AuthorUnrestricted(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to match author schedules: %w", err)
+	}
+
+	if unrestricted {
+		cmd.logger.DebugContext(ctx, "author is not bound to any schedule, skipping", "author_email", email)
+		return nil
+	}
+
+	if cmd.cfg.Config, err = cmd.cfg.ForCurrentBranch(ctx); err != nil {
+		return fmt.Errorf("unable to apply branch policies: %w", err)
+	}
+
 	if cmd.cfg.AuthorDate == "" {
 		cmd.logger.DebugContext(ctx, "no author date provided, nothing to rewrite, skipping")
 	}
@@ -69,13 +91,18 @@ func (cmd *cmdPostCommit) Execute(ctx context.Context, _, _ []string) error {
 		return fmt.Errorf("could not resolve commit date: %w", err)
 	}
 
-	schedule, err := workhours.ParseWeeklySchedule(cmd.cfg.Schedule)
+	if cmd.cfg.Config, err = cmd.cfg.ResolveScheduleFile(); err != nil {
+		return err
+	}
+
+	schedule, err := cmd.cfg.WorkSchedule()
 	if err != nil {
 		return fmt.Errorf("unable to parse schedule: %w", err)
 	}
 
-	if cmd.cfg.InvertSchedule {
-		schedule = schedule.Inverted()
+	mask, err := cmd.cfg.MaskLocation()
+	if err != nil {
+		return fmt.Errorf("unable to parse timezone mask: %w", err)
 	}
 
 	if schedule.CurrentShift(authorDate) != nil {
@@ -86,13 +113,13 @@ func (cmd *cmdPostCommit) Execute(ctx context.Context, _, _ []string) error {
 		)
 
 		if !cmd.cfg.Force {
-			return nil
+			return cmd.maskLastCommitTimezone(ctx, mask)
 		}
 	}
 
 	if !cmd.cfg.AllowOvertime || !cmd.cfg.FakeValidTime {
 		cmd.logger.WarnContext(ctx, "commit created outside of schedule, author time is over time", "previous_shift", schedule.PreviousShift(authorDate).String(), "next_shift", schedule.NextShift(authorDate).String())
-		return nil
+		return cmd.maskLastCommitTimezone(ctx, mask)
 	}
 
 	previousShift := schedule.PreviousShift(authorDate)
@@ -102,6 +129,14 @@ func (cmd *cmdPostCommit) Execute(ctx context.Context, _, _ []string) error {
 		return fmt.Errorf("unable to calculate probable commit time: %w", err)
 	}
 
+	if !cmd.cfg.UseScheduleTimezone {
+		probableTime = probableTime.In(authorDate.Location())
+	}
+
+	if mask != nil {
+		probableTime = probableTime.In(mask)
+	}
+
 	cmd.logger.InfoContext(ctx, "changing last commit date to avoid overtime",
 		"shift", previousShift.String(),
 		"old", authorDate.Format(time.DateTime),
@@ -113,50 +148,73 @@ func (cmd *cmdPostCommit) Execute(ctx context.Context, _, _ []string) error {
 		return nil
 	}
 
-	if err := git.AmendLastCommitDate(ctx, probableTime); err != nil {
-		return fmt.Errorf("unable to amend last commit date: %w", err)
-	}
-
-	return nil
+	return cmd.amendLastCommitDate(ctx, probableTime, probableTime)
 }
 
-// fakeAuthorDate generates a realistic commit time within the previous work shift boundaries
-// while preserving chronological order with the previous commit.
-//
-// Rules:
-// - starts with previous work shift time boundaries as the base window
-// - adjusts lower bound to previous commit time if it's after shift start (maintains chronological order)
-// - adjusts upper bound to previous commit time + 10min if previous commit is after shift end
-// - caps upper bound to current time if upper bound is in the future
-// - for tight windows (<45min): distributes randomly across available time
-// - for normal windows: adds realistic delay (5-15min base + 0-30min random) to simulate human timing
-func (*cmdPostCommit) fakeAuthorDate(ctx context.Context, authorLastShift *workhours.WorkingShift) (time.Time, error) {
-	lastCommitTime, err := git.GetCommitTime(ctx, "HEAD", 1)
+// maskLastCommitTimezone rewrites the author and committer dates of the last commit
+// with the provided timezone, without changing the dates themselves.
+func (cmd *cmdPostCommit) maskLastCommitTimezone(ctx context.Context, mask *time.Location) error {
+	if mask == nil {
+		return nil
+	}
+
+	commit, err := git.GetCommit(ctx, "HEAD")
 	if err != nil {
-		return time.Time{}, fmt.Errorf("could not get last commit time: %w", err)
+		return fmt.Errorf("could not get last commit: %w", err)
 	}
 
-	lowerBound := authorLastShift[0]
-	upperBound := authorLastShift[1]
+	authorDate, committerDate := commit.AuthorDate.In(mask), commit.CommitterDate.In(mask)
+
+	if commit.AuthorDate.Format(time.RFC3339) == authorDate.Format(time.RFC3339) && commit.CommitterDate.Format(time.RFC3339) == committerDate.Format(time.RFC3339) {
+		cmd.logger.DebugContext(ctx, "last commit dates already carry the timezone mask")
+		return nil
+	}
+
+	cmd.logger.InfoContext(ctx, "changing last commit timezone to hide location",
+		"old_author_date", commit.AuthorDate.Format(time.RFC3339),
+		"new_author_date", authorDate.Format(time.RFC3339),
+	)
 
-	if lastCommitTime.After(lowerBound) {
-		lowerBound = lastCommitTime
+	if cmd.cfg.DryRun {
+		cmd.logger.WarnContext(ctx, "amending last commit timezone skipped due to dry-run")
+		return nil
 	}
 
-	if lastCommitTime.After(upperBound) {
-		upperBound = lastCommitTime.Add(time.Minute * 10)
+	return cmd.amendLastCommitDate(ctx, authorDate, committerDate)
+}
+
+// amendLastCommitDate amends the dates of the last commit, and records its original dates in the audit log.
+func (cmd *cmdPostCommit) amendLastCommitDate(ctx context.Context, authorDate, committerDate time.Time) error {
+	commit, err := git.GetCommit(ctx, "HEAD")
+	if err != nil {
+		return fmt.Errorf("could not get last commit: %w", err)
 	}
 
-	if now := time.Now(); upperBound.After(now) {
-		upperBound = now
+	if err := git.AmendLastCommitDate(ctx, authorDate, committerDate); err != nil {
+		return fmt.Errorf("unable to amend last commit date: %w", err)
 	}
 
-	if timeAvailable := upperBound.Sub(lowerBound); timeAvailable < time.Minute*45 {
-		return lowerBound.Add(time.Duration(rand.Int64N(int64(timeAvailable)))), nil
+	amended, err := git.GetCommit(ctx, "HEAD")
+	if err != nil {
+		return fmt.Errorf("could not get amended commit: %w", err)
+	}
+
+	handlershared.RecordRewrittenCommits(ctx, cmd.logger, cmd.cfg.Config, "post-commit",
+		[]git.Commit{commit},
+		map[string]git.CommitDates{commit.Hash: {Author: authorDate, Committer: committerDate}},
+		map[string]string{commit.Hash: amended.Hash},
+	)
+
+	return nil
+}
+
+// fakeAuthorDate generates a realistic commit time within the previous work shift boundaries
+// while preserving chronological order with the previous commit.
+func (*cmdPostCommit) fakeAuthorDate(ctx context.Context, authorLastShift *workhours.WorkingShift) (time.Time, error) {
+	lastCommitTime, err := git.GetCommitTime(ctx, "HEAD", 1)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not get last commit time: %w", err)
 	}
 
-	return lowerBound.Add(
-		(time.Minute * time.Duration(5+rand.Int64N(10))) + // 5-15mn
-			time.Duration(rand.Int64N(int64(30*time.Minute))), // 0-30mn
-	), nil
+	return handlershared.FakeDateWithinShift(authorLastShift, lastCommitTime, time.Now()), nil
 }
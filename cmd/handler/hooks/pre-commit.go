@@ -10,20 +10,21 @@ import (
 	"github.com/krostar/cli"
 	clidi "github.com/krostar/cli/di"
 
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
 	"github.com/krostar/git-workhours/internal/git"
-	"github.com/krostar/git-workhours/internal/workhours"
 )
 
 // PreCommit returns the pre-commit hook command.
 func PreCommit() cli.Command { return new(cmdPreCommit) }
 
 type cmdPreCommit struct {
-	cfg    cmdPreCommitConfig
-	logger *slog.Logger
+	cfg       cmdPreCommitConfig
+	logger    *slog.Logger
+	localHook *localHook
 }
 
 type cmdPreCommitConfig struct {
-	hookSharedConfig
+	handlershared.Config
 
 	AuthorDate string `env:"GIT_AUTHOR_DATE"`
 }
@@ -42,31 +43,46 @@ func (cmd *cmdPreCommit) Hook() *cli.Hook {
 	return &cli.Hook{
 		BeforeCommandExecution: func(ctx context.Context) error {
 			return errors.Join(
-				sourceConfigHook(&cmd.cfg)(ctx),
-				clidi.Invoke(ctx, func(shared *hookSharedConfig, logger *slog.Logger) {
+				handlershared.SourceConfigHook(&cmd.cfg)(ctx),
+				clidi.Invoke(ctx, func(shared *handlershared.Config, logger *slog.Logger, localHook *localHook) {
+					cmd.localHook = localHook
 					cmd.logger = logger.With("hook", "pre-commit")
-					cmd.cfg.hookSharedConfig = *shared
+					cmd.cfg.Config = *shared
 				}),
 			)
 		},
 	}
 }
 
-func (cmd *cmdPreCommit) Execute(ctx context.Context, _, _ []string) error {
+func (cmd *cmdPreCommit) Execute(ctx context.Context, args, _ []string) error {
+	return cmd.localHook.wrap(ctx, cmd.logger, "pre-commit", args, func() error { return cmd.execute(ctx) })
+}
+
+func (cmd *cmdPreCommit) execute(ctx context.Context) error {
+	unrestricted, email, err := cmd.cfg.AuthorUnrestricted(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to match author schedules: %w", err)
+	}
+
+	if unrestricted {
+		cmd.logger.DebugContext(ctx, "author is not bound to any schedule, skipping", "author_email", email)
+		return nil
+	}
+
+	if cmd.cfg.Config, err = cmd.cfg.ForCurrentBranch(ctx); err != nil {
+		return fmt.Errorf("unable to apply branch policies: %w", err)
+	}
+
 	authorDate, err := git.ResolveDate(ctx, cmd.cfg.AuthorDate)
 	if err != nil {
 		return fmt.Errorf("could not resolve commit date: %w", err)
 	}
 
-	schedule, err := workhours.ParseWeeklySchedule(cmd.cfg.Schedule)
+	schedule, err := cmd.cfg.WorkSchedule()
 	if err != nil {
 		return fmt.Errorf("unable to parse schedule: %w", err)
 	}
 
-	if cmd.cfg.InvertSchedule {
-		schedule = schedule.Inverted()
-	}
-
 	if current := schedule.CurrentShift(authorDate); current != nil {
 		cmd.logger.DebugContext(ctx, "author time is within work schedule",
 			"shift", current.String(),
@@ -79,7 +95,7 @@ func (cmd *cmdPreCommit) Execute(ctx context.Context, _, _ []string) error {
 	cmd.logger.WarnContext(ctx, "author time is over time", "previous_shift", schedule.PreviousShift(authorDate).String(), "next_shift", schedule.NextShift(authorDate).String())
 
 	if !cmd.cfg.AllowOvertime {
-		return cli.NewErrorWithExitStatus(fmt.Errorf("can't commit now, previous shift ended %s ago", time.Since(schedule.PreviousShift(authorDate)[1]).Truncate(time.Minute).String()), 3)
+		return cli.NewErrorWithExitStatus(fmt.Errorf("can't commit now, previous shift ended %s ago", handlershared.FormatDuration(time.Since(schedule.PreviousShift(authorDate)[1]))), 3)
 	}
 
 	return nil
@@ -2,47 +2,289 @@ package handlerhooks
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/krostar/cli"
 	clidi "github.com/krostar/cli/di"
 
-	"github.com/krostar/git-workhours/internal/workhours"
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
+	"github.com/krostar/git-workhours/internal/git"
 )
 
 // PrePush returns the pre-push hook command.
 func PrePush() cli.Command { return new(cmdPrePush) }
 
 type cmdPrePush struct {
-	cfg    hookSharedConfig
-	logger *slog.Logger
+	cfg       cmdPrePushConfig
+	logger    *slog.Logger
+	localHook *localHook
+}
+
+type cmdPrePushConfig struct {
+	handlershared.Config `env:"-"`
+
+	FakeValidTime       bool
+	UseScheduleTimezone bool
+	DryRun              bool
 }
 
 func (*cmdPrePush) Description() string {
-	return "Pre-push hook that validates pushes are made within work hours."
+	return "Pre-push hook that validates pushes and pushed commits are made within work hours, and rewrites pushed commits made outside of work hours."
+}
+
+func (*cmdPrePush) Usage() string {
+	return "<remote name> <remote url>"
+}
+
+func (cmd *cmdPrePush) Flags() []cli.Flag {
+	return []cli.Flag{
+		cli.NewBuiltinFlag("dry-run", "", &cmd.cfg.DryRun, "Don't perform any writing operations"),
+		cli.NewBuiltinFlag("fake-valid-time", "", &cmd.cfg.FakeValidTime, "Automatically adjust pushed commit times to fall within work hours"),
+		cli.NewBuiltinFlag("use-schedule-timezone", "", &cmd.cfg.UseScheduleTimezone, "Adjusted commit times carry the schedule timezone offset instead of the original one"),
+	}
 }
 
 func (cmd *cmdPrePush) Hook() *cli.Hook {
 	return &cli.Hook{
 		BeforeCommandExecution: func(ctx context.Context) error {
-			return clidi.Invoke(ctx, func(shared *hookSharedConfig, logger *slog.Logger) {
-				cmd.logger = logger.With("cmd", "pre-push")
-				cmd.cfg = *shared
-			})
+			return errors.Join(
+				handlershared.SourceConfigHook(&cmd.cfg)(ctx),
+				clidi.Invoke(ctx, func(shared *handlershared.Config, logger *slog.Logger, localHook *localHook) {
+					cmd.localHook = localHook
+					cmd.logger = logger.With("cmd", "pre-push")
+					cmd.cfg.Config = *shared
+				}),
+			)
 		},
 	}
 }
 
-func (cmd *cmdPrePush) Execute(ctx context.Context, _, _ []string) error {
-	schedule, err := workhours.ParseWeeklySchedule(cmd.cfg.Schedule)
+func (cmd *cmdPrePush) Execute(ctx context.Context, args, _ []string) error {
+	return cmd.localHook.wrap(ctx, cmd.logger, "pre-push", args, func() error { return cmd.execute(ctx, args) })
+}
+
+func (cmd *cmdPrePush) execute(ctx context.Context, args []string) error {
+	refs, err := git.ParsePrePushInput(os.Stdin)
 	if err != nil {
-		return fmt.Errorf("unable to parse schedule: %w", err)
+		return fmt.Errorf("unable to parse pushed refs: %w", err)
+	}
+
+	var remote string
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	policies, err := cmd.pushPolicies(refs)
+	if err != nil {
+		return fmt.Errorf("unable to apply branch policies: %w", err)
+	}
+
+	for _, policy := range policies {
+		if err := cmd.checkPush(ctx, remote, policy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pushPolicy holds some of the pushed refs, on which the same remote and branch policies apply.
+type pushPolicy struct {
+	// branch is one of the branches the refs are pushed to, from which the branch policies of all refs are found.
+	branch string
+	refs   []git.PushedRef
+}
+
+// pushPolicies groups the pushed refs by the branch policies applying to the branch each ref is pushed to.
+func (cmd *cmdPrePush) pushPolicies(refs []git.PushedRef) ([]pushPolicy, error) {
+	if len(refs) == 0 {
+		return []pushPolicy{{}}, nil
+	}
+
+	var (
+		policies []pushPolicy
+		patterns [][]string
+	)
+
+	for _, ref := range refs {
+		var branch string
+		if name, isBranch := strings.CutPrefix(ref.RemoteRef, "refs/heads/"); isBranch {
+			branch = name
+		}
+
+		refPatterns, err := cmd.cfg.BranchPatterns(branch)
+		if err != nil {
+			return nil, err
+		}
+
+		idx := slices.IndexFunc(patterns, func(p []string) bool { return slices.Equal(p, refPatterns) })
+		if idx < 0 {
+			idx = len(policies)
+			policies = append(policies, pushPolicy{branch: branch})
+			patterns = append(patterns, refPatterns)
+		}
+
+		policies[idx].refs = append(policies[idx].refs, ref)
+	}
+
+	return policies, nil
+}
+
+// policyConfig returns the configuration overridden by the remote policy, and by the policies of the branch.
+func policyConfig(cfg cmdPrePushConfig, remote, branch string) (cmdPrePushConfig, error) {
+	var err error
+
+	if cfg.Config, err = cfg.ForRemote(remote); err != nil {
+		return cmdPrePushConfig{}, err
+	}
+
+	if cfg.Config, err = cfg.ForBranch(branch); err != nil {
+		return cmdPrePushConfig{}, err
+	}
+
+	return cfg, nil
+}
+
+// authorCommits holds some of the pushed commits, on which the same configuration applies.
+type authorCommits struct {
+	cfg     cmdPrePushConfig
+	commits []git.Commit
+}
+
+// groupByAuthor groups the commits by the configuration applying to their author, see handlershared.Config.ForAuthor,
+// overridden by the policies of the refs. Commits of authors not bound to any schedule are left out.
+func (cmd *cmdPrePush) groupByAuthor(ctx context.Context, remote string, policy pushPolicy, commits []git.Commit) ([]authorCommits, error) {
+	type author struct {
+		cfg   cmdPrePushConfig
+		bound bool
+	}
+
+	var (
+		groups  []authorCommits
+		authors = make(map[string]author)
+	)
+
+	for _, commit := range commits {
+		a, found := authors[commit.AuthorEmail]
+		if !found {
+			a.cfg = cmd.cfg
+
+			var err error
+			if a.cfg.Config, a.bound, err = cmd.cfg.ForAuthor(ctx, commit.AuthorEmail); err != nil {
+				return nil, fmt.Errorf("unable to match author schedules: %w", err)
+			}
+
+			if a.cfg, err = policyConfig(a.cfg, remote, policy.branch); err != nil {
+				return nil, fmt.Errorf("unable to apply remote and branch policies: %w", err)
+			}
+
+			if a.cfg.Config, err = a.cfg.ResolveScheduleFile(); err != nil {
+				return nil, err
+			}
+
+			authors[commit.AuthorEmail] = a
+		}
+
+		if !a.bound {
+			cmd.logger.DebugContext(ctx, "commit author is not bound to any schedule, skipping", "commit", commit.Hash, "author_email", commit.AuthorEmail)
+			continue
+		}
+
+		idx := slices.IndexFunc(groups, func(group authorCommits) bool { return reflect.DeepEqual(group.cfg, a.cfg) })
+		if idx < 0 {
+			idx = len(groups)
+			groups = append(groups, authorCommits{cfg: a.cfg})
+		}
+
+		groups[idx].commits = append(groups[idx].commits, commit)
+	}
+
+	return groups, nil
+}
+
+// checkPush validates the push time and the pushed commits of the refs against the configuration applying to them.
+func (cmd *cmdPrePush) checkPush(ctx context.Context, remote string, policy pushPolicy) error {
+	cfg, err := policyConfig(cmd.cfg, remote, policy.branch)
+	if err != nil {
+		return fmt.Errorf("unable to apply remote and branch policies: %w", err)
+	}
+
+	if err := cmd.checkPushTime(ctx, cfg); err != nil {
+		return err
+	}
+
+	commits, err := listPushedCommits(ctx, remote, policy.refs)
+	if err != nil {
+		return fmt.Errorf("unable to list pushed commits: %w", err)
 	}
 
-	if cmd.cfg.InvertSchedule {
-		schedule = schedule.Inverted()
+	groups, err := cmd.groupByAuthor(ctx, remote, policy, commits)
+	if err != nil {
+		return err
+	}
+
+	var (
+		rewrites []authorCommits
+		overtime []handlershared.OvertimeCommit
+	)
+
+	for _, group := range groups {
+		if group.cfg.AllowOvertime && group.cfg.FakeValidTime {
+			rewrites = append(rewrites, group)
+			continue
+		}
+
+		schedule, err := group.cfg.WorkSchedule()
+		if err != nil {
+			return fmt.Errorf("unable to parse schedule: %w", err)
+		}
+
+		for _, oc := range handlershared.FindOvertimeCommits(schedule, group.commits) {
+			cmd.logger.WarnContext(ctx, "pushed commit is over time",
+				"commit", oc.Commit.Hash,
+				"author_date", oc.Commit.AuthorDate.Format(time.DateTime),
+				"committer_date", oc.Commit.CommitterDate.Format(time.DateTime),
+			)
+
+			if !group.cfg.AllowOvertime {
+				overtime = append(overtime, oc)
+			}
+		}
+	}
+
+	if len(overtime) > 0 {
+		return cli.NewErrorWithExitStatus(fmt.Errorf("can't push, %d commits were made outside of work hours:\n%s", len(overtime), handlershared.FormatOvertimeCommits(overtime)), 3)
+	}
+
+	if len(rewrites) > 0 {
+		return cmd.rewritePushedCommits(ctx, cfg, policy.refs, commits, rewrites)
+	}
+
+	return nil
+}
+
+// checkPushTime validates the push time against the configuration applying to the author pushing.
+func (cmd *cmdPrePush) checkPushTime(ctx context.Context, cfg cmdPrePushConfig) error {
+	unrestricted, email, err := cfg.AuthorUnrestricted(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to match author schedules: %w", err)
+	}
+
+	if unrestricted {
+		cmd.logger.DebugContext(ctx, "author is not bound to any schedule, skipping push time check", "author_email", email)
+		return nil
+	}
+
+	schedule, err := cfg.WorkSchedule()
+	if err != nil {
+		return fmt.Errorf("unable to parse schedule: %w", err)
 	}
 
 	pushTime := time.Now()
@@ -50,10 +292,118 @@ func (cmd *cmdPrePush) Execute(ctx context.Context, _, _ []string) error {
 	if schedule.CurrentShift(pushTime) == nil {
 		cmd.logger.WarnContext(ctx, "push time is over time", "previous_shift", schedule.PreviousShift(pushTime).String(), "next_shift", schedule.NextShift(pushTime).String())
 
-		if !cmd.cfg.AllowOvertime {
-			return cli.NewErrorWithExitStatus(fmt.Errorf("can't push now, previous shift ended %s ago", time.Since(schedule.PreviousShift(pushTime)[1]).Truncate(time.Minute).String()), 3)
+		if !cfg.AllowOvertime {
+			return cli.NewErrorWithExitStatus(fmt.Errorf("can't push now, previous shift ended %s ago", handlershared.FormatDuration(time.Since(schedule.PreviousShift(pushTime)[1]))), 3)
 		}
 	}
 
 	return nil
 }
+
+// rewritePushedCommits rewrites the dates of the pushed commits made outside of the schedule applying to their author,
+// oldest first, keeping dates chronologically ordered. As git already decided what to push, the push is aborted
+// whenever commits are rewritten so that it can be retried with the rewritten commits.
+func (cmd *cmdPrePush) rewritePushedCommits(ctx context.Context, cfg cmdPrePushConfig, refs []git.PushedRef, commits []git.Commit, groups []authorCommits) error {
+	fakers := make(map[string]handlershared.CommitDatesFaker)
+
+	for _, group := range groups {
+		schedule, err := group.cfg.WorkSchedule()
+		if err != nil {
+			return fmt.Errorf("unable to parse schedule: %w", err)
+		}
+
+		mask, err := group.cfg.MaskLocation()
+		if err != nil {
+			return fmt.Errorf("unable to parse timezone mask: %w", err)
+		}
+
+		for _, commit := range group.commits {
+			fakers[commit.Hash] = handlershared.CommitDatesFaker{
+				Schedule:            schedule,
+				Mask:                mask,
+				UseScheduleTimezone: group.cfg.UseScheduleTimezone,
+			}
+		}
+	}
+
+	// all pushed commits are faked at once, whatever their author, so that dates keep increasing across authors
+	dates, err := handlershared.CommitDatesFaker{
+		ForCommit: func(commit git.Commit) (handlershared.CommitDatesFaker, bool) {
+			faker, found := fakers[commit.Hash]
+			return faker, found
+		},
+		Logger: cmd.logger,
+	}.FakeDates(ctx, commits)
+	if err != nil {
+		return fmt.Errorf("unable to fake pushed commits dates: %w", err)
+	}
+
+	if len(dates) == 0 {
+		return nil
+	}
+
+	if cfg.DryRun {
+		cmd.logger.WarnContext(ctx, "rewriting pushed commits skipped due to dry-run", "commits", len(dates))
+		return nil
+	}
+
+	rewritten, err := git.RewriteCommitDates(ctx, commits, dates)
+	if err != nil {
+		return fmt.Errorf("unable to rewrite pushed commits: %w", err)
+	}
+
+	for _, group := range groups {
+		handlershared.RecordRewrittenCommits(ctx, cmd.logger, group.cfg.Config, "pre-push", group.commits, dates, rewritten)
+	}
+
+	var instructions []string
+
+	for _, ref := range refs {
+		newHash, found := rewritten[ref.LocalHash]
+		if ref.IsDeletion() || !found {
+			continue
+		}
+
+		if ref.LocalRef != "HEAD" && !strings.HasPrefix(ref.LocalRef, "refs/") {
+			instructions = append(instructions, fmt.Sprintf("%s has been rewritten to %s, push it to %s", ref.LocalHash, newHash, ref.RemoteRef))
+			continue
+		}
+
+		if err := git.UpdateRef(ctx, ref.LocalRef, newHash, ref.LocalHash, "git-workhours: rewrite commits made outside of work hours"); err != nil {
+			return fmt.Errorf("unable to update rewritten ref: %w", err)
+		}
+
+		instructions = append(instructions, fmt.Sprintf("%s has been rewritten from %s to %s", ref.LocalRef, ref.LocalHash, newHash))
+	}
+
+	return cli.NewErrorWithExitStatus(fmt.Errorf("%d pushed commits were made outside of work hours and have been rewritten, push again to send the rewritten commits:\n  %s", len(dates), strings.Join(instructions, "\n  ")), 3)
+}
+
+// listPushedCommits lists the commits about to be pushed, oldest first.
+// Commits already known to be on the remote, either because they are reachable
+// from the remote ref being updated or from the remote-tracking refs, are excluded.
+func listPushedCommits(ctx context.Context, remote string, refs []git.PushedRef) ([]git.Commit, error) {
+	var included, excluded []string
+
+	for _, ref := range refs {
+		if ref.IsDeletion() {
+			continue
+		}
+
+		included = append(included, ref.LocalHash)
+
+		if !ref.IsCreation() && git.CommitExists(ctx, ref.RemoteHash) {
+			excluded = append(excluded, ref.RemoteHash)
+		}
+	}
+
+	if len(included) == 0 {
+		return nil, nil
+	}
+
+	if remote != "" {
+		excluded = append(excluded, "--remotes="+remote)
+	}
+
+	return git.ListCommits(ctx, append(append(append([]string{"--reverse", "--topo-order"}, included...), "--not"), excluded...)...)
+}
@@ -0,0 +1,143 @@
+package handlerhooks
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/krostar/cli"
+	clidi "github.com/krostar/cli/di"
+
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
+	"github.com/krostar/git-workhours/internal/git"
+)
+
+// PreReceive returns the server-side pre-receive hook command.
+func PreReceive() cli.Command { return new(cmdPreReceive) }
+
+type cmdPreReceive struct {
+	cfg    handlershared.Config
+	logger *slog.Logger
+}
+
+func (*cmdPreReceive) Description() string {
+	return "Server-side pre-receive hook that rejects pushes containing commits made outside of work hours."
+}
+
+func (cmd *cmdPreReceive) Hook() *cli.Hook {
+	return &cli.Hook{
+		BeforeCommandExecution: func(ctx context.Context) error {
+			return clidi.Invoke(ctx, func(shared *handlershared.Config, logger *slog.Logger) {
+				cmd.logger = logger.With("hook", "pre-receive")
+				cmd.cfg = *shared
+			})
+		},
+	}
+}
+
+func (cmd *cmdPreReceive) Execute(ctx context.Context, _, _ []string) error {
+	refs, err := git.ParsePreReceiveInput(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("unable to parse received refs: %w", err)
+	}
+
+	return checkReceivedRefs(ctx, cmd.logger, cmd.cfg, refs)
+}
+
+// checkReceivedRefs rejects the received refs introducing commits whose author or committer dates are outside of the schedule
+// applying to their author, overridden by the policies of the received branch.
+func checkReceivedRefs(ctx context.Context, logger *slog.Logger, cfg handlershared.Config, refs []git.ReceivedRef) error {
+	var rejections []string
+
+	for _, ref := range refs {
+		overtime, rejected, err := findReceivedOvertimeCommits(ctx, cfg, ref)
+		if err != nil {
+			return fmt.Errorf("unable to check commits received for %s: %w", ref.Ref, err)
+		}
+
+		for _, oc := range overtime {
+			logger.WarnContext(ctx, "received commit is over time",
+				"ref", ref.Ref,
+				"commit", oc.Commit.Hash,
+				"author_date", oc.Commit.AuthorDate.Format(time.DateTime),
+				"committer_date", oc.Commit.CommitterDate.Format(time.DateTime),
+			)
+		}
+
+		if rejected {
+			rejections = append(rejections, fmt.Sprintf("%s: %d commits were made outside of work hours:\n%s", ref.Ref, len(overtime), handlershared.FormatOvertimeCommits(overtime)))
+		}
+	}
+
+	if len(rejections) == 0 {
+		return nil
+	}
+
+	return cli.NewErrorWithExitStatus(errors.New("push rejected:\n"+strings.Join(rejections, "\n")), 3)
+}
+
+// findReceivedOvertimeCommits returns the commits introduced by the received ref that are over time, in the received order,
+// and whether some of them are not allowed to be.
+// Received commits are the ones reachable from the new hash that are not yet reachable from any existing ref.
+func findReceivedOvertimeCommits(ctx context.Context, cfg handlershared.Config, ref git.ReceivedRef) ([]handlershared.OvertimeCommit, bool, error) {
+	if ref.IsDeletion() {
+		return nil, false, nil
+	}
+
+	args := []string{ref.NewHash, "--not", "--all"}
+	if !ref.IsCreation() {
+		args = append(args, ref.OldHash)
+	}
+
+	commits, err := git.ListCommits(ctx, append([]string{"--reverse", "--topo-order"}, args...)...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	branch, isBranch := strings.CutPrefix(ref.Ref, "refs/heads/")
+	if !isBranch {
+		branch = ""
+	}
+
+	groups, err := cfg.GroupByAuthor(ctx, branch, commits)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var (
+		overtime []handlershared.OvertimeCommit
+		rejected bool
+	)
+
+	for _, group := range groups {
+		schedule, err := group.Config.WorkSchedule()
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to parse schedule: %w", err)
+		}
+
+		groupOvertime := handlershared.FindOvertimeCommits(schedule, group.Commits)
+		if len(groupOvertime) > 0 && !group.Config.AllowOvertime {
+			rejected = true
+		}
+
+		overtime = append(overtime, groupOvertime...)
+	}
+
+	// commits of the different authors are listed in the received order
+	positions := make(map[string]int, len(commits))
+	for i, commit := range commits {
+		positions[commit.Hash] = i
+	}
+
+	slices.SortFunc(overtime, func(a, b handlershared.OvertimeCommit) int {
+		return cmp.Compare(positions[a.Commit.Hash], positions[b.Commit.Hash])
+	})
+
+	return overtime, rejected, nil
+}
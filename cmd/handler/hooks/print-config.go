@@ -2,27 +2,37 @@ package handlerhooks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/krostar/cli"
+	clicfg "github.com/krostar/cli/cfg"
+	sourcedefault "github.com/krostar/cli/cfg/source/default"
+	sourceenv "github.com/krostar/cli/cfg/source/env"
 	clidi "github.com/krostar/cli/di"
+	"go.yaml.in/yaml/v3"
 
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
+	gitconfig "github.com/krostar/git-workhours/internal/git/config"
 	"github.com/krostar/git-workhours/internal/workhours"
 )
 
 // PrintConfig returns the print-config hook command.
-func PrintConfig() cli.Command { return new(cmdPrintConfig) }
+func PrintConfig() cli.Command { return &cmdPrintConfig{output: "text"} }
 
 type cmdPrintConfig struct {
-	cfg hookSharedConfig
+	root       *cmdRoot
+	showOrigin bool
+	output     string
 }
 
 func (cmd *cmdPrintConfig) Hook() *cli.Hook {
 	return &cli.Hook{
 		BeforeCommandExecution: func(ctx context.Context) error {
-			return clidi.Invoke(ctx, func(shared *hookSharedConfig) { cmd.cfg = *shared })
+			return clidi.Invoke(ctx, func(root *cmdRoot) { cmd.root = root })
 		},
 	}
 }
@@ -31,40 +41,293 @@ func (*cmdPrintConfig) Description() string {
 	return "Print configuration values"
 }
 
-func (cmd *cmdPrintConfig) Execute(_ context.Context, _, _ []string) error {
-	fmt.Println("Hook Configuration")
-	fmt.Printf("  Schedule: %q\n", cmd.cfg.Schedule)
-	fmt.Printf("  InvertSchedule: %t\n", cmd.cfg.InvertSchedule)
-	fmt.Printf("  AllowOvertime: %t\n", cmd.cfg.AllowOvertime)
+func (cmd *cmdPrintConfig) Flags() []cli.Flag {
+	return []cli.Flag{
+		cli.NewBuiltinFlag("show-origin", "", &cmd.showOrigin, "Show which source set each configuration value, with the file and line of git configuration values"),
+		cli.NewBuiltinFlag("output", "o", &cmd.output, "Output format (text, json, yaml)"),
+	}
+}
+
+// printConfigSection is a named list of resolved configuration fields.
+type printConfigSection struct {
+	name   string
+	fields []configField
+}
+
+// printedConfig is the machine-readable representation of the configuration.
+type printedConfig struct {
+	Config   map[string]any               `json:"config"            yaml:"config"`
+	Hooks    map[string]map[string]any    `json:"hooks"             yaml:"hooks"`
+	Origins  map[string]map[string]string `json:"origins,omitempty" yaml:"origins,omitempty"`
+	Schedule printedSchedule              `json:"schedule"          yaml:"schedule"`
+}
+
+// printedSchedule is the machine-readable representation of the parsed schedule.
+// Weekly is the configured schedule, InvertedWeekly is its inversion, hooks enforcing the latter if InvertSchedule is set.
+type printedSchedule struct {
+	Timezone       string             `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	InvertSchedule bool               `json:"invert_schedule"    yaml:"invert_schedule"`
+	Weekly         printedWeek        `json:"weekly"             yaml:"weekly"`
+	InvertedWeekly printedWeek        `json:"inverted_weekly"    yaml:"inverted_weekly"`
+	Exceptions     []printedException `json:"exceptions"         yaml:"exceptions"`
+	TimeOff        []string           `json:"time_off"           yaml:"time_off"`
+}
+
+// printedWeek is the machine-readable representation of a weekly schedule, Raw being re-usable as a schedule configuration.
+type printedWeek struct {
+	Raw       string                           `json:"raw"       yaml:"raw"`
+	Sunday    []workhours.WorkingShiftSchedule `json:"sunday"    yaml:"sunday"`
+	Monday    []workhours.WorkingShiftSchedule `json:"monday"    yaml:"monday"`
+	Tuesday   []workhours.WorkingShiftSchedule `json:"tuesday"   yaml:"tuesday"`
+	Wednesday []workhours.WorkingShiftSchedule `json:"wednesday" yaml:"wednesday"`
+	Thursday  []workhours.WorkingShiftSchedule `json:"thursday"  yaml:"thursday"`
+	Friday    []workhours.WorkingShiftSchedule `json:"friday"    yaml:"friday"`
+	Saturday  []workhours.WorkingShiftSchedule `json:"saturday"  yaml:"saturday"`
+}
+
+// printedException is the machine-readable representation of a schedule exception.
+type printedException struct {
+	Dates  string                           `json:"dates"  yaml:"dates"`
+	Shifts []workhours.WorkingShiftSchedule `json:"shifts" yaml:"shifts"`
+}
+
+func (cmd *cmdPrintConfig) Execute(ctx context.Context, _, _ []string) error {
+	if cmd.output != "text" && cmd.output != "json" && cmd.output != "yaml" {
+		return cli.NewErrorWithHelp(fmt.Errorf("unknown output format %q", cmd.output))
+	}
+
+	var gitOrigins []gitconfig.Origin
+
+	if cmd.showOrigin {
+		origins, err := gitconfig.Origins(ctx, "wh")
+		if err != nil {
+			return fmt.Errorf("unable to get git configuration origins: %w", err)
+		}
+
+		values := make(map[string][]string, len(origins))
+		for _, origin := range origins {
+			key := strings.ToLower(origin.Key)
+			values[key] = append(values[key], origin.Value)
+		}
+
+		selected, err := handlershared.SelectNamedValues(ctx, values)
+		if err != nil {
+			return fmt.Errorf("unable to select named git configuration values: %w", err)
+		}
+
+		gitOrigins = gitconfig.ResolveNamedOrigins(origins, selected)
+	}
 
-	schedule, err := workhours.ParseWeeklySchedule(cmd.cfg.Schedule)
+	sections := []printConfigSection{{name: "config", fields: configFields(&cmd.root.cfg, cmd.root.flags, gitOrigins, true)}}
+
+	for _, hook := range []struct {
+		name string
+		cfg  any
+	}{
+		{name: "pre-commit", cfg: new(cmdPreCommitConfig)},
+		{name: "post-commit", cfg: new(cmdPostCommitConfig)},
+		{name: "pre-push", cfg: new(cmdPrePushConfig)},
+	} {
+		if err := sourceHookSpecificConfig(ctx, hook.cfg); err != nil {
+			return fmt.Errorf("unable to source %s configuration: %w", hook.name, err)
+		}
+
+		sections = append(sections, printConfigSection{name: hook.name, fields: configFields(hook.cfg, nil, gitOrigins, false)})
+	}
+
+	// the structured output describes the configured schedule along with its inversion, whichever hooks enforce
+	configured := cmd.root.cfg.Config
+	configured.InvertSchedule = false
+
+	schedule, err := configured.WorkSchedule()
 	if err != nil {
 		return fmt.Errorf("unable to parse schedule: %w", err)
 	}
 
-	if cmd.cfg.InvertSchedule {
-		schedule = schedule.Inverted()
+	switch cmd.output {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(cmd.printed(sections, schedule))
+	case "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+
+		if err := encoder.Encode(cmd.printed(sections, schedule)); err != nil {
+			return err
+		}
+
+		return encoder.Close()
+	default:
+		if cmd.root.cfg.InvertSchedule {
+			schedule = schedule.Inverted()
+		}
+
+		cmd.printText(sections, schedule)
+
+		return nil
+	}
+}
+
+func (cmd *cmdPrintConfig) printed(sections []printConfigSection, schedule workhours.Schedule) printedConfig {
+	printed := printedConfig{
+		Hooks: make(map[string]map[string]any),
+		Schedule: printedSchedule{
+			InvertSchedule: cmd.root.cfg.InvertSchedule,
+			Weekly:         newPrintedWeek(schedule.Weekly),
+			InvertedWeekly: newPrintedWeek(schedule.Weekly.Inverted()),
+			Exceptions:     []printedException{},
+			TimeOff:        []string{},
+		},
 	}
 
-	fmt.Print("\nParsed Schedule:")
+	if cmd.showOrigin {
+		printed.Origins = make(map[string]map[string]string)
+	}
+
+	for _, section := range sections {
+		values := make(map[string]any, len(section.fields))
+
+		for _, field := range section.fields {
+			values[strings.ToLower(field.Name)] = field.Value
+
+			if printed.Origins != nil {
+				if printed.Origins[section.name] == nil {
+					printed.Origins[section.name] = make(map[string]string, len(section.fields))
+				}
+
+				printed.Origins[section.name][strings.ToLower(field.Name)] = field.Origin
+			}
+		}
+
+		if section.name == "config" {
+			printed.Config = values
+		} else {
+			printed.Hooks[section.name] = values
+		}
+	}
+
+	if schedule.Location != nil {
+		printed.Schedule.Timezone = schedule.Location.String()
+	}
+
+	for _, exception := range schedule.Exceptions {
+		printed.Schedule.Exceptions = append(printed.Schedule.Exceptions, printedException{Dates: exception.DateRange.String(), Shifts: exception.Shifts})
+	}
+
+	for _, timeOff := range schedule.TimeOff {
+		printed.Schedule.TimeOff = append(printed.Schedule.TimeOff, timeOff.String())
+	}
+
+	return printed
+}
+
+func newPrintedWeek(ws workhours.WeeklySchedule) printedWeek {
+	return printedWeek{
+		Raw:       ws.String(),
+		Sunday:    ws[time.Sunday],
+		Monday:    ws[time.Monday],
+		Tuesday:   ws[time.Tuesday],
+		Wednesday: ws[time.Wednesday],
+		Thursday:  ws[time.Thursday],
+		Friday:    ws[time.Friday],
+		Saturday:  ws[time.Saturday],
+	}
+}
+
+func (cmd *cmdPrintConfig) printText(sections []printConfigSection, schedule workhours.Schedule) {
+	for i, section := range sections {
+		if i == 0 {
+			fmt.Println("Hook Configuration")
+		} else {
+			fmt.Printf("\n%s Hook Configuration\n", section.name)
+		}
+
+		cmd.printFields(section.fields)
+	}
+
+	fmt.Println("\nParsed Schedule:")
 
 	days := []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
 
-	for day, shifts := range schedule {
-		fmt.Printf("  %s: ", days[day])
+	for day, shifts := range schedule.Weekly {
+		fmt.Printf("  %s: %s\n", days[day], formatShifts(shifts))
+	}
 
-		if len(shifts) == 0 {
-			fmt.Println("No working hours")
-			continue
+	if len(schedule.Exceptions) > 0 {
+		fmt.Println("\nParsed Exceptions:")
+
+		for _, exception := range schedule.Exceptions {
+			fmt.Printf("  %s: %s\n", exception.DateRange.String(), formatShifts(exception.Shifts))
+		}
+	}
+
+	if len(schedule.TimeOff) > 0 {
+		fmt.Println("\nParsed Time Off:")
+
+		for _, timeOff := range schedule.TimeOff {
+			fmt.Printf("  %s\n", timeOff.String())
+		}
+	}
+}
+
+func (cmd *cmdPrintConfig) printFields(fields []configField) {
+	for _, field := range fields {
+		var value string
+
+		switch v := field.Value.(type) {
+		case string, []string:
+			value = fmt.Sprintf("%q", v)
+		default:
+			value = fmt.Sprintf("%v", v)
+		}
+
+		if cmd.showOrigin {
+			fmt.Printf("  %s: %s (%s)\n", field.Name, value, field.Origin)
+		} else {
+			fmt.Printf("  %s: %s\n", field.Name, value)
 		}
+	}
+}
+
+// sourceHookSpecificConfig sources the configuration of a hook the same way the hook does, except from flags
+// as they are specific to the hook command.
+func sourceHookSpecificConfig(ctx context.Context, cfg any) error {
+	switch cfg := cfg.(type) {
+	case *cmdPreCommitConfig:
+		return sourceConfigWithoutFlags(cfg)(ctx)
+	case *cmdPostCommitConfig:
+		return sourceConfigWithoutFlags(cfg)(ctx)
+	case *cmdPrePushConfig:
+		return sourceConfigWithoutFlags(cfg)(ctx)
+	default:
+		return fmt.Errorf("unsupported hook configuration %T", cfg)
+	}
+}
 
-		shiftStrs := make([]string, len(shifts))
-		for i, shift := range shifts {
-			shiftStrs[i] = fmt.Sprintf("%v-%v", shift[0].Truncate(time.Minute), shift[1].Truncate(time.Minute))
+func sourceConfigWithoutFlags[T any](dest *T) func(context.Context) error {
+	return clicfg.BeforeCommandExecutionHook(dest,
+		sourcedefault.Source[T](),
+		gitconfig.Source[T]("wh", gitconfig.SourceWithIgnoreConfigError(), gitconfig.SourceWithNamedSelectorFunc(handlershared.SelectNamedValues), gitconfig.SourceWithSubsectionFields("remote", "branch")),
+		sourceenv.Source[T]("WH"),
+	)
+}
+
+func formatShifts(shifts []workhours.WorkingShiftSchedule) string {
+	if len(shifts) == 0 {
+		return "No working hours"
+	}
+
+	shiftStrs := make([]string, len(shifts))
+	for i, shift := range shifts {
+		if shift[1] >= 24*time.Hour {
+			shiftStrs[i] = fmt.Sprintf("%v-%v (next day)", shift[0].Truncate(time.Minute), (shift[1] - 24*time.Hour).Truncate(time.Minute))
+			continue
 		}
 
-		fmt.Printf("%s\n", strings.Join(shiftStrs, ", "))
+		shiftStrs[i] = fmt.Sprintf("%v-%v", shift[0].Truncate(time.Minute), shift[1].Truncate(time.Minute))
 	}
 
-	return nil
+	return strings.Join(shiftStrs, ", ")
 }
@@ -8,13 +8,23 @@ import (
 
 	"github.com/krostar/cli"
 	clidi "github.com/krostar/cli/di"
+
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
 )
 
 // Root returns the root command for hook-related operations.
 func Root() cli.Command { return new(cmdRoot) }
 
 type cmdRoot struct {
-	cfg hookSharedConfig
+	cfg cmdRootConfig
+	// flags are the initialized persistent flags, used to tell which configuration values were set by flags.
+	flags []cli.Flag
+}
+
+type cmdRootConfig struct {
+	handlershared.Config `env:"^"`
+
+	RunLocalHook bool
 }
 
 func (*cmdRoot) Description() string {
@@ -22,17 +32,19 @@ func (*cmdRoot) Description() string {
 }
 
 func (cmd *cmdRoot) PersistentFlags() []cli.Flag {
-	return []cli.Flag{
-		cli.NewBuiltinFlag("schedule", "", &cmd.cfg.Schedule, "Work schedule in format 'slice of time shift', eg: ',8h-12h+13h-18h,9h-18h,,,,'"),
-		cli.NewBuiltinFlag("inverse-schedule", "", &cmd.cfg.InvertSchedule, "Invert the work schedule"),
-		cli.NewBuiltinFlag("allow-overtime", "", &cmd.cfg.AllowOvertime, "Allow commits outside work hours with warning"),
-	}
+	return append(handlershared.Flags(&cmd.cfg.Config),
+		cli.NewBuiltinFlag("run-local-hook", "", &cmd.cfg.RunLocalHook, "Run the repository's own hook of the same name afterwards, when hooks are installed through core.hooksPath"),
+	)
 }
 
 func (cmd *cmdRoot) PersistentHook() *cli.PersistentHook {
 	return &cli.PersistentHook{
 		BeforeCommandExecution: func(ctx context.Context) error {
-			clidi.AddProvider(ctx, func() *hookSharedConfig { return &cmd.cfg })
+			_, cmd.flags = cli.GetInitializedFlagsFromContext(ctx)
+
+			clidi.AddProvider(ctx, func() *cmdRoot { return cmd })
+			clidi.AddProvider(ctx, func() *handlershared.Config { return &cmd.cfg.Config })
+			clidi.AddProvider(ctx, func() *localHook { return &localHook{enabled: cmd.cfg.RunLocalHook} })
 
 			if os.Getenv("GIT_WH_ONGOING") != "" {
 				return cli.NewErrorWithExitStatus(errors.New("skipping to avoid infinite hook recursion"), 0)
@@ -42,7 +54,7 @@ func (cmd *cmdRoot) PersistentHook() *cli.PersistentHook {
 				return fmt.Errorf("could not set GIT_WH_ONGOING=true: %w", err)
 			}
 
-			return sourceConfigHook(&cmd.cfg)(ctx)
+			return handlershared.SourceConfigHook(&cmd.cfg)(ctx)
 		},
 	}
 }
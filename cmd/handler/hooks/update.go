@@ -0,0 +1,48 @@
+package handlerhooks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/krostar/cli"
+	clidi "github.com/krostar/cli/di"
+
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
+	"github.com/krostar/git-workhours/internal/git"
+)
+
+// Update returns the server-side update hook command.
+func Update() cli.Command { return new(cmdUpdate) }
+
+type cmdUpdate struct {
+	cfg    handlershared.Config
+	logger *slog.Logger
+}
+
+func (*cmdUpdate) Description() string {
+	return "Server-side update hook that rejects ref updates containing commits made outside of work hours."
+}
+
+func (*cmdUpdate) Usage() string {
+	return "<ref name> <old hash> <new hash>"
+}
+
+func (cmd *cmdUpdate) Hook() *cli.Hook {
+	return &cli.Hook{
+		BeforeCommandExecution: func(ctx context.Context) error {
+			return clidi.Invoke(ctx, func(shared *handlershared.Config, logger *slog.Logger) {
+				cmd.logger = logger.With("hook", "update")
+				cmd.cfg = *shared
+			})
+		},
+	}
+}
+
+func (cmd *cmdUpdate) Execute(ctx context.Context, args, _ []string) error {
+	if len(args) != 3 {
+		return cli.NewErrorWithHelp(fmt.Errorf("expected 3 arguments, got %d", len(args)))
+	}
+
+	return checkReceivedRefs(ctx, cmd.logger, cmd.cfg, []git.ReceivedRef{{Ref: args[0], OldHash: args[1], NewHash: args[2]}})
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/krostar/cli"
+
+	"github.com/krostar/git-workhours/internal/git"
+)
+
+const (
+	// hookShimMarker identifies hooks written by the install command.
+	hookShimMarker = "# installed by git-workhours"
+	// hookBackupSuffix is appended to hooks found in place of the installed ones, so they can be chained and restored.
+	hookBackupSuffix = ".pre-git-workhours"
+)
+
+// defaultHooks are the hooks installed when none are specified.
+var defaultHooks = []string{"pre-commit", "post-commit", "pre-push"}
+
+// Install returns the command installing hook shims.
+func Install() cli.Command { return new(cmdInstall) }
+
+type cmdInstall struct {
+	cfg cmdInstallConfig
+}
+
+type cmdInstallConfig struct {
+	Global bool
+	Hooks  []string
+	DryRun bool
+}
+
+func (*cmdInstall) Description() string {
+	return "Install git-workhours hooks in the current repository, or globally through core.hooksPath. Existing hooks are kept and run after git-workhours."
+}
+
+func (cmd *cmdInstall) Flags() []cli.Flag {
+	return []cli.Flag{
+		cli.NewBuiltinFlag("global", "", &cmd.cfg.Global, "Install hooks in the global hooks directory set by core.hooksPath, setting it if needed"),
+		cli.NewBuiltinSliceFlag("hooks", "", &cmd.cfg.Hooks, "Hooks to install, eg: 'pre-commit,post-commit,pre-push'"),
+		cli.NewBuiltinFlag("dry-run", "", &cmd.cfg.DryRun, "Only print what would change"),
+	}
+}
+
+func (cmd *cmdInstall) Execute(ctx context.Context, _, _ []string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("unable to get git-workhours executable path: %w", err)
+	}
+
+	dir, actions, err := hooksDirectory(ctx, cmd.cfg.Global)
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range hooksOrDefault(cmd.cfg.Hooks) {
+		path := filepath.Join(dir, hook)
+
+		if isHookShim(path) {
+			actions = append(actions, fileAction{description: path + " is already installed"})
+			continue
+		}
+
+		if _, err := os.Stat(path); err == nil {
+			if _, err := os.Stat(path + hookBackupSuffix); err == nil {
+				return fmt.Errorf("unable to move existing hook %s, %s already exists: remove one of them before installing", path, path+hookBackupSuffix)
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("unable to check existing hook backup: %w", err)
+			}
+
+			actions = append(actions, fileAction{
+				description: fmt.Sprintf("move existing hook %s to %s, it will run after git-workhours", path, path+hookBackupSuffix),
+				apply:       func() error { return os.Rename(path, path+hookBackupSuffix) },
+			})
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("unable to check existing hook: %w", err)
+		}
+
+		actions = append(actions, fileAction{
+			description: "write hook " + path,
+			apply: func() error {
+				if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gosec // hooks directory must be readable by git
+					return err
+				}
+
+				return os.WriteFile(path, []byte(hookShim(executable, hook)), 0o755) //nolint:gosec // hooks must be executable
+			},
+		})
+	}
+
+	if !cmd.cfg.Global {
+		if hooksPath, err := git.GetConfigPath(ctx, git.ConfigScopeAll, "core.hooksPath"); err == nil && hooksPath != "" {
+			actions = append(actions, fileAction{description: fmt.Sprintf("warning: core.hooksPath is set to %s, hooks of the repository are not run by git", hooksPath)})
+		}
+	}
+
+	return applyFileActions(actions, cmd.cfg.DryRun)
+}
+
+// hooksDirectory returns the directory in which hooks are installed, with the actions needed to make git use it.
+// Globally installed hooks go in the directory set by core.hooksPath, defaulting to $XDG_DATA_HOME/git/hooks.
+func hooksDirectory(ctx context.Context, global bool) (string, []fileAction, error) {
+	if !global {
+		gitDir, err := git.CommonDir(ctx)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return filepath.Join(gitDir, "hooks"), nil, nil
+	}
+
+	hooksPath, err := git.GetConfigPath(ctx, git.ConfigScopeGlobal, "core.hooksPath")
+	if err != nil {
+		return "", nil, err
+	}
+
+	if hooksPath != "" {
+		return hooksPath, nil, nil
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", nil, fmt.Errorf("unable to get home directory: %w", err)
+		}
+
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+
+	hooksPath = filepath.Join(dataHome, "git", "hooks")
+
+	return hooksPath, []fileAction{{
+		description: "set global core.hooksPath to " + hooksPath,
+		apply: func() error {
+			return errors.Join(
+				git.SetConfig(ctx, git.ConfigScopeGlobal, "core.hooksPath", hooksPath),
+				git.SetConfig(ctx, git.ConfigScopeGlobal, "wh.installedHooksPath", hooksPath),
+			)
+		},
+	}}, nil
+}
+
+// hookShim returns the content of the hook script running git-workhours, then the hook it replaced, if any.
+// Hooks reading their standard input get the same input.
+func hookShim(executable, hook string) string {
+	command := fmt.Sprintf("'%s' hooks %s \"$@\"", strings.ReplaceAll(executable, "'", `'\''`), hook)
+	previous := `"$0` + hookBackupSuffix + `"`
+
+	var script strings.Builder
+
+	script.WriteString("#!/usr/bin/env sh\n")
+	script.WriteString(hookShimMarker + ", remove it with 'git-workhours uninstall'\n")
+
+	if slices.Contains([]string{"pre-push", "pre-receive"}, hook) {
+		script.WriteString("stdin=\"$(cat)\"\n")
+		script.WriteString("printf '%s\\n' \"$stdin\" | " + command + " || exit $?\n")
+		script.WriteString("if [ -x " + previous + " ]; then printf '%s\\n' \"$stdin\" | " + previous + " \"$@\"; fi\n")
+	} else {
+		script.WriteString(command + " || exit $?\n")
+		script.WriteString("if [ -x " + previous + " ]; then exec " + previous + " \"$@\"; fi\n")
+	}
+
+	return script.String()
+}
+
+// isHookShim returns whether the file at path is a hook written by the install command.
+func isHookShim(path string) bool {
+	content, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(content), hookShimMarker)
+}
+
+func hooksOrDefault(hooks []string) []string {
+	hooks = slices.DeleteFunc(slices.Clone(hooks), func(hook string) bool { return hook == "" })
+	if len(hooks) == 0 {
+		return defaultHooks
+	}
+
+	return hooks
+}
+
+// fileAction describes a change made on the filesystem or the configuration, apply is nil for informative actions.
+type fileAction struct {
+	description string
+	apply       func() error
+}
+
+// applyFileActions applies and prints the actions, or only prints them in dry-run.
+func applyFileActions(actions []fileAction, dryRun bool) error {
+	for _, action := range actions {
+		if action.apply == nil {
+			fmt.Println(action.description)
+			continue
+		}
+
+		if dryRun {
+			fmt.Println("would " + action.description)
+			continue
+		}
+
+		if err := action.apply(); err != nil {
+			return fmt.Errorf("unable to %s: %w", action.description, err)
+		}
+
+		fmt.Println(action.description)
+	}
+
+	return nil
+}
@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/krostar/cli"
+	clidi "github.com/krostar/cli/di"
+
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
+	"github.com/krostar/git-workhours/internal/git"
+	"github.com/krostar/git-workhours/internal/timesheet"
+)
+
+// Report returns the command estimating worked time from commit dates.
+func Report() cli.Command { return new(cmdReport) }
+
+type cmdReport struct {
+	cfg    cmdReportConfig
+	logger *slog.Logger
+}
+
+type cmdReportConfig struct {
+	handlershared.Config `env:"^"`
+
+	Author      string
+	AllAuthors  bool
+	Since       string
+	Until       string
+	SessionGap  string
+	FirstCommit string
+	Output      string
+}
+
+func (cfg *cmdReportConfig) SetDefault() {
+	options := timesheet.DefaultOptions()
+
+	cfg.SessionGap = options.SessionGap.String()
+	cfg.FirstCommit = options.FirstCommit.String()
+	cfg.Output = "table"
+}
+
+func (*cmdReport) Description() string {
+	return "Estimate worked time per day and per week from the real dates of commits of a revision range, defaulting to HEAD, " +
+		"and report the time worked outside of work hours."
+}
+
+func (*cmdReport) Usage() string {
+	return "[<revision range>]"
+}
+
+func (cmd *cmdReport) Flags() []cli.Flag {
+	return append(handlershared.Flags(&cmd.cfg.Config),
+		cli.NewBuiltinFlag("author", "", &cmd.cfg.Author, "Only report commits authored with this email, defaults to user.email"),
+		cli.NewBuiltinFlag("all-authors", "", &cmd.cfg.AllAuthors, "Report commits of all authors"),
+		cli.NewBuiltinFlag("since", "", &cmd.cfg.Since, "Only report commits really authored after this date, in any format git understands, eg: '2026-10-01', 'last monday'"),
+		cli.NewBuiltinFlag("until", "", &cmd.cfg.Until, "Only report commits really authored before this date, in any format git understands"),
+		cli.NewBuiltinFlag("session-gap", "", &cmd.cfg.SessionGap, "Maximum duration between two commits of the same work session"),
+		cli.NewBuiltinFlag("first-commit", "", &cmd.cfg.FirstCommit, "Duration assumed to be worked before the first commit of a work session"),
+		cli.NewBuiltinFlag("output", "o", &cmd.cfg.Output, "Output format (table, csv, json)"),
+	)
+}
+
+func (cmd *cmdReport) Hook() *cli.Hook {
+	return &cli.Hook{
+		BeforeCommandExecution: func(ctx context.Context) error {
+			if err := clidi.Invoke(ctx, func(logger *slog.Logger) { cmd.logger = logger.With("cmd", "report") }); err != nil {
+				return err
+			}
+
+			return handlershared.SourceConfigHook(&cmd.cfg)(ctx)
+		},
+	}
+}
+
+func (cmd *cmdReport) Execute(ctx context.Context, args, _ []string) error {
+	if len(args) > 1 {
+		return cli.NewErrorWithHelp(fmt.Errorf("expected at most one revision range, got %d arguments", len(args)))
+	}
+
+	if cmd.cfg.Output != "table" && cmd.cfg.Output != "csv" && cmd.cfg.Output != "json" {
+		return cli.NewErrorWithHelp(fmt.Errorf("unknown output format %q", cmd.cfg.Output))
+	}
+
+	revisionRange := "HEAD"
+	if len(args) == 1 {
+		revisionRange = args[0]
+	}
+
+	schedule, err := cmd.cfg.WorkSchedule()
+	if err != nil {
+		return fmt.Errorf("unable to parse schedule: %w", err)
+	}
+
+	options, err := cmd.options()
+	if err != nil {
+		return err
+	}
+
+	commits, err := cmd.commits(ctx, revisionRange)
+	if err != nil {
+		return err
+	}
+
+	weeks := timesheet.Build(schedule, commits, options)
+
+	switch cmd.cfg.Output {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(newReportWeeks(weeks))
+	case "csv":
+		return printReportCSV(weeks)
+	default:
+		return printReportTable(weeks)
+	}
+}
+
+func (cmd *cmdReport) options() (timesheet.Options, error) {
+	sessionGap, err := time.ParseDuration(cmd.cfg.SessionGap)
+	if err != nil {
+		return timesheet.Options{}, cli.NewErrorWithHelp(fmt.Errorf("invalid session gap: %w", err))
+	}
+
+	firstCommit, err := time.ParseDuration(cmd.cfg.FirstCommit)
+	if err != nil {
+		return timesheet.Options{}, cli.NewErrorWithHelp(fmt.Errorf("invalid first commit duration: %w", err))
+	}
+
+	return timesheet.Options{SessionGap: sessionGap, FirstCommit: firstCommit}, nil
+}
+
+// commits returns the commits of the revision range made by the author within the requested period, with their real author dates.
+func (cmd *cmdReport) commits(ctx context.Context, revisionRange string) ([]timesheet.Commit, error) {
+	filter := realCommitsFilter{since: cmd.cfg.Since, until: cmd.cfg.Until}
+
+	switch {
+	case cmd.cfg.AllAuthors:
+	case cmd.cfg.Author != "":
+		filter.authors = []string{cmd.cfg.Author}
+	default:
+		email, err := git.GetConfig(ctx, git.ConfigScopeAll, "user.email")
+		if err != nil {
+			return nil, err
+		}
+
+		if email == "" {
+			return nil, cli.NewErrorWithHelp(errors.New("user.email is not set, use --author or --all-authors"))
+		}
+
+		filter.authors = []string{email}
+	}
+
+	gitCommits, err := listRealCommits(ctx, cmd.logger, cmd.cfg.Config, revisionRange, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]timesheet.Commit, len(gitCommits))
+	for i, commit := range gitCommits {
+		commits[i] = timesheet.Commit{Hash: commit.Hash, Date: commit.AuthorDate}
+	}
+
+	return commits, nil
+}
+
+// reportWeek is the machine-readable representation of a week of the report, durations are in seconds.
+type reportWeek struct {
+	Week      string      `json:"week"`
+	Scheduled int64       `json:"scheduled_seconds"`
+	Worked    int64       `json:"worked_seconds"`
+	Overtime  int64       `json:"overtime_seconds"`
+	Days      []reportDay `json:"days"`
+}
+
+// reportDay is the machine-readable representation of a day of the report.
+type reportDay struct {
+	Date      string        `json:"date"`
+	Scheduled int64         `json:"scheduled_seconds"`
+	Worked    int64         `json:"worked_seconds"`
+	Overtime  int64         `json:"overtime_seconds"`
+	Shifts    []reportShift `json:"shifts"`
+}
+
+// reportShift is the machine-readable representation of the commits made in a shift, or over time when the shift is not set.
+type reportShift struct {
+	Start   *time.Time `json:"start,omitempty"`
+	End     *time.Time `json:"end,omitempty"`
+	Commits []string   `json:"commits"`
+	Worked  int64      `json:"worked_seconds"`
+}
+
+func newReportWeeks(weeks []timesheet.Week) []reportWeek {
+	report := []reportWeek{}
+
+	for _, week := range weeks {
+		rw := reportWeek{
+			Week:      formatReportWeek(week),
+			Scheduled: int64(week.Scheduled.Seconds()),
+			Worked:    int64(week.Worked.Seconds()),
+			Overtime:  int64(week.Overtime.Seconds()),
+		}
+
+		for _, day := range week.Days {
+			rd := reportDay{
+				Date:      day.Date.String(),
+				Scheduled: int64(day.Scheduled.Seconds()),
+				Worked:    int64(day.Worked.Seconds()),
+				Overtime:  int64(day.Overtime.Seconds()),
+			}
+
+			for _, shift := range day.Shifts {
+				rs := reportShift{Commits: []string{}, Worked: int64(shift.Worked.Seconds())}
+				if shift.Shift != nil {
+					rs.Start, rs.End = &shift.Shift[0], &shift.Shift[1]
+				}
+
+				for _, commit := range shift.Commits {
+					rs.Commits = append(rs.Commits, commit.Hash)
+				}
+
+				rd.Shifts = append(rd.Shifts, rs)
+			}
+
+			rw.Days = append(rw.Days, rd)
+		}
+
+		report = append(report, rw)
+	}
+
+	return report
+}
+
+// printReportTable prints a table with a line per day and per shift, followed by the total of the week.
+func printReportTable(weeks []timesheet.Week) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tSHIFT\tCOMMITS\tWORKED\tOVERTIME\tSCHEDULED")
+
+	for _, week := range weeks {
+		for _, day := range week.Days {
+			for i, shift := range day.Shifts {
+				date, scheduled := "", ""
+				if i == 0 {
+					date, scheduled = day.Date.String()+" "+day.Date.Weekday().String()[:3], handlershared.FormatDuration(day.Scheduled)
+				}
+
+				name, overtime := "over time", handlershared.FormatDuration(shift.Worked)
+				if shift.Shift != nil {
+					name, overtime = shift.Shift[0].Format("15:04")+"-"+shift.Shift[1].Format("15:04"), ""
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", date, name, len(shift.Commits), handlershared.FormatDuration(shift.Worked), overtime, scheduled)
+			}
+		}
+
+		fmt.Fprintf(w, "%s\ttotal\t\t%s\t%s\t%s\n", formatReportWeek(week), handlershared.FormatDuration(week.Worked), handlershared.FormatDuration(week.Overtime), handlershared.FormatDuration(week.Scheduled))
+	}
+
+	return w.Flush()
+}
+
+// printReportCSV prints a line per day, durations being in hours to be easily summed in spreadsheets.
+func printReportCSV(weeks []timesheet.Week) error {
+	w := csv.NewWriter(os.Stdout)
+
+	if err := w.Write([]string{"week", "date", "commits", "worked_hours", "overtime_hours", "scheduled_hours"}); err != nil {
+		return err
+	}
+
+	hours := func(d time.Duration) string { return strconv.FormatFloat(d.Hours(), 'f', 2, 64) }
+
+	for _, week := range weeks {
+		for _, day := range week.Days {
+			if err := w.Write([]string{
+				formatReportWeek(week), day.Date.String(), strconv.Itoa(day.Commits()),
+				hours(day.Worked), hours(day.Overtime), hours(day.Scheduled),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
+
+func formatReportWeek(week timesheet.Week) string {
+	return fmt.Sprintf("%04d-W%02d", week.Year, week.Week)
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/krostar/cli"
+	clidi "github.com/krostar/cli/di"
+
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
+	"github.com/krostar/git-workhours/internal/git"
+)
+
+// Rewrite returns the command rewriting dates of commits of a revision range made outside of work hours.
+func Rewrite() cli.Command { return new(cmdRewrite) }
+
+type cmdRewrite struct {
+	cfg    cmdRewriteConfig
+	logger *slog.Logger
+}
+
+type cmdRewriteConfig struct {
+	handlershared.Config `env:"^"`
+
+	UseScheduleTimezone bool
+	DryRun              bool
+}
+
+func (*cmdRewrite) Description() string {
+	return "Rewrite dates of commits of a revision range made outside of work hours, keeping a backup of rewritten branches under refs/original/."
+}
+
+func (*cmdRewrite) Usage() string {
+	return "<revision range>"
+}
+
+func (cmd *cmdRewrite) Flags() []cli.Flag {
+	return append(handlershared.Flags(&cmd.cfg.Config),
+		cli.NewBuiltinFlag("dry-run", "", &cmd.cfg.DryRun, "Only print the dates that would be rewritten"),
+		cli.NewBuiltinFlag("use-schedule-timezone", "", &cmd.cfg.UseScheduleTimezone, "Adjusted commit times carry the schedule timezone offset instead of the original one"),
+	)
+}
+
+func (cmd *cmdRewrite) Hook() *cli.Hook {
+	return &cli.Hook{
+		BeforeCommandExecution: func(ctx context.Context) error {
+			if err := clidi.Invoke(ctx, func(logger *slog.Logger) { cmd.logger = logger.With("cmd", "rewrite") }); err != nil {
+				return err
+			}
+
+			return handlershared.SourceConfigHook(&cmd.cfg)(ctx)
+		},
+	}
+}
+
+func (cmd *cmdRewrite) Execute(ctx context.Context, args, _ []string) error {
+	if len(args) != 1 {
+		return cli.NewErrorWithHelp(fmt.Errorf("expected a single revision range, got %d arguments", len(args)))
+	}
+
+	rangeRefs, err := git.RevisionRangeRefs(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	// only branches and a detached HEAD are rewritten, tags and remote-tracking branches are never moved
+	var refs []string
+	for _, ref := range rangeRefs {
+		if ref != "HEAD" && !strings.HasPrefix(ref, "refs/heads/") {
+			cmd.logger.WarnContext(ctx, "ref is not a branch, skipping it", "ref", ref)
+			continue
+		}
+
+		refs = append(refs, ref)
+	}
+
+	if len(refs) == 0 {
+		return fmt.Errorf("revision range %s does not include any branch to rewrite", args[0])
+	}
+
+	commits, err := git.ListCommits(ctx, "--reverse", "--topo-order", args[0], "--")
+	if err != nil {
+		return fmt.Errorf("unable to list commits of %s: %w", args[0], err)
+	}
+
+	groups, err := cmd.cfg.GroupByAuthor(ctx, "", commits)
+	if err != nil {
+		return err
+	}
+
+	fakers := make(map[string]handlershared.CommitDatesFaker)
+
+	for _, group := range groups {
+		schedule, err := group.Config.WorkSchedule()
+		if err != nil {
+			return fmt.Errorf("unable to parse schedule: %w", err)
+		}
+
+		mask, err := group.Config.MaskLocation()
+		if err != nil {
+			return fmt.Errorf("unable to parse timezone mask: %w", err)
+		}
+
+		for _, commit := range group.Commits {
+			fakers[commit.Hash] = handlershared.CommitDatesFaker{
+				Schedule:            schedule,
+				Mask:                mask,
+				UseScheduleTimezone: cmd.cfg.UseScheduleTimezone,
+			}
+		}
+	}
+
+	dates, err := handlershared.CommitDatesFaker{
+		ForCommit: func(commit git.Commit) (handlershared.CommitDatesFaker, bool) {
+			faker, found := fakers[commit.Hash]
+			return faker, found
+		},
+		Logger: cmd.logger,
+	}.FakeDates(ctx, commits)
+	if err != nil {
+		return fmt.Errorf("unable to fake commits dates: %w", err)
+	}
+
+	if len(dates) == 0 {
+		cmd.logger.InfoContext(ctx, "all commits are within work hours, nothing to rewrite", "commits", len(commits))
+		return nil
+	}
+
+	if err := printRewrittenDates(commits, dates); err != nil {
+		return fmt.Errorf("unable to print rewritten dates: %w", err)
+	}
+
+	if cmd.cfg.DryRun {
+		cmd.logger.WarnContext(ctx, "rewriting commits skipped due to dry-run", "commits", len(dates))
+		return nil
+	}
+
+	for _, ref := range refs {
+		if _, exists := git.ResolveRef(ctx, backupRef(ref)); exists {
+			return fmt.Errorf("a backup of %s already exists, remove it with 'git update-ref -d %s' before rewriting again", ref, backupRef(ref))
+		}
+	}
+
+	rewritten, err := git.RewriteCommitDates(ctx, commits, dates)
+	if err != nil {
+		return fmt.Errorf("unable to rewrite commits: %w", err)
+	}
+
+	for _, group := range groups {
+		handlershared.RecordRewrittenCommits(ctx, cmd.logger, group.Config, "rewrite", group.Commits, dates, rewritten)
+	}
+
+	for _, ref := range refs {
+		oldHash, _ := git.ResolveRef(ctx, ref)
+
+		newHash, found := rewritten[oldHash]
+		if !found {
+			continue
+		}
+
+		if err := git.UpdateRef(ctx, backupRef(ref), oldHash, "", "git-workhours: backup before rewriting commits made outside of work hours"); err != nil {
+			return fmt.Errorf("unable to backup ref: %w", err)
+		}
+
+		if err := git.UpdateRef(ctx, ref, newHash, oldHash, "git-workhours: rewrite commits made outside of work hours"); err != nil {
+			return fmt.Errorf("unable to update rewritten ref: %w", err)
+		}
+
+		fmt.Printf("%s has been rewritten from %s to %s, undo with 'git update-ref %s %s'\n", ref, oldHash, newHash, ref, backupRef(ref))
+	}
+
+	return nil
+}
+
+// backupRef returns the ref in which the ref is saved before being rewritten.
+func backupRef(ref string) string {
+	return "refs/original/" + ref
+}
+
+// printRewrittenDates prints a table of the commits dates before and after being rewritten, oldest commit first.
+func printRewrittenDates(commits []git.Commit, dates map[string]git.CommitDates) error {
+	const layout = "2006-01-02 15:04:05 -0700"
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMMIT\tAUTHOR DATE\tNEW AUTHOR DATE\tCOMMITTER DATE\tNEW COMMITTER DATE")
+
+	for _, commit := range commits {
+		newDates, found := dates[commit.Hash]
+		if !found {
+			continue
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			commit.Hash[:min(len(commit.Hash), 12)],
+			commit.AuthorDate.Format(layout), newDates.Author.Format(layout),
+			commit.CommitterDate.Format(layout), newDates.Committer.Format(layout),
+		)
+	}
+
+	return w.Flush()
+}
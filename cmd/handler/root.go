@@ -11,13 +11,16 @@ import (
 	clidi "github.com/krostar/cli/di"
 	"github.com/mattn/go-isatty"
 	"gitlab.com/greyxor/slogor"
+
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
 )
 
 // Root returns the root command handler for the git-workhours CLI.
 func Root() cli.Command { return new(cmdRoot) }
 
 type cmdRoot struct {
-	cfg cmdRootConfig
+	cfg     cmdRootConfig
+	logFile *os.File
 }
 
 func (*cmdRoot) Description() string {
@@ -26,11 +29,20 @@ func (*cmdRoot) Description() string {
 
 type cmdRootConfig struct {
 	LoggerVerbosity string
+	LogFormat       string
+	LogFile         string
+}
+
+func (cfg *cmdRootConfig) SetDefault() {
+	cfg.LoggerVerbosity = "info"
+	cfg.LogFormat = "text"
 }
 
 func (cmd *cmdRoot) PersistentFlags() []cli.Flag {
 	return []cli.Flag{
 		cli.NewBuiltinFlag("log-verbosity", "v", &cmd.cfg.LoggerVerbosity, "Set log verbosity level (debug, info, warn, error)"),
+		cli.NewBuiltinFlag("log-format", "", &cmd.cfg.LogFormat, "Set log format (text, json)"),
+		cli.NewBuiltinFlag("log-file", "", &cmd.cfg.LogFile, "Append logs to this file, in addition to the terminal"),
 	}
 }
 
@@ -39,10 +51,14 @@ func (cmd *cmdRoot) PersistentHook() *cli.PersistentHook {
 		BeforeFlagsDefinition: func(ctx context.Context) error {
 			clidi.InitializeContainer(ctx)
 
-			cmd.cfg = cmdRootConfig{LoggerVerbosity: "info"}
+			cmd.cfg.SetDefault()
 			return nil
 		},
 		BeforeCommandExecution: func(ctx context.Context) error {
+			if err := handlershared.SourceConfigHook(&cmd.cfg)(ctx); err != nil {
+				return fmt.Errorf("unable to source configuration: %w", err)
+			}
+
 			{ // logger related
 				logger, err := cmd.createLogger()
 				if err != nil {
@@ -53,6 +69,13 @@ func (cmd *cmdRoot) PersistentHook() *cli.PersistentHook {
 				clidi.AddProvider(ctx, func() *slog.Logger { return logger })
 			}
 
+			return nil
+		},
+		AfterCommandExecution: func(context.Context) error {
+			if cmd.logFile != nil {
+				return cmd.logFile.Close()
+			}
+
 			return nil
 		},
 	}
@@ -78,12 +101,36 @@ func (cmd *cmdRoot) createLogger() (*slog.Logger, error) {
 		return nil, fmt.Errorf("unknown logger level: %s", cmd.cfg.LoggerVerbosity)
 	}
 
-	if isatty.IsTerminal(os.Stdout.Fd()) {
-		return slog.New(slogor.NewHandler(os.Stdout,
+	var handlers []slog.Handler
+
+	switch {
+	case cmd.cfg.LogFormat != "text" && cmd.cfg.LogFormat != "json":
+		return nil, fmt.Errorf("unknown logger format: %s", cmd.cfg.LogFormat)
+	case isatty.IsTerminal(os.Stdout.Fd()) && cmd.cfg.LogFormat == "text":
+		handlers = append(handlers, slogor.NewHandler(os.Stdout,
 			slogor.SetLevel(loggerLeveler.Level()),
 			slogor.SetTimeFormat(time.Kitchen),
-		)), nil
+		))
+	case isatty.IsTerminal(os.Stdout.Fd()):
+		handlers = append(handlers, newLogHandler(os.Stdout, cmd.cfg.LogFormat, loggerLeveler.Level()))
+	default:
+		// when run from IDEs, GUI clients, or CI, warnings are still shown by git which prints hooks' standard error
+		handlers = append(handlers, newLogHandler(os.Stderr, cmd.cfg.LogFormat, max(loggerLeveler.Level(), slog.LevelWarn)))
+	}
+
+	if cmd.cfg.LogFile != "" {
+		file, err := openLogFile(cmd.cfg.LogFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open log file: %w", err)
+		}
+
+		cmd.logFile = file
+		handlers = append(handlers, newLogHandler(file, cmd.cfg.LogFormat, loggerLeveler.Level()))
+	}
+
+	if len(handlers) == 1 {
+		return slog.New(handlers[0]), nil
 	}
 
-	return slog.New(slog.DiscardHandler), nil
+	return slog.New(multiLogHandler(handlers)), nil
 }
@@ -0,0 +1,71 @@
+package handlershared
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/krostar/git-workhours/internal/audit"
+	"github.com/krostar/git-workhours/internal/git"
+)
+
+// AuditLogPath returns the path of the audit log, or an empty string if it is disabled.
+func (cfg Config) AuditLogPath() (string, error) {
+	switch cfg.AuditLog {
+	case "off":
+		return "", nil
+	case "":
+		return audit.DefaultPath()
+	default:
+		return cfg.AuditLog, nil
+	}
+}
+
+// RecordRewrittenCommits appends to the audit log an entry for each commit whose dates have been rewritten,
+// the configuration being the one used to rewrite them, with its schedule file resolved, see Config.ResolveScheduleFile.
+// Commits being already rewritten, failing to record them is only logged.
+func RecordRewrittenCommits(ctx context.Context, logger *slog.Logger, cfg Config, origin string, commits []git.Commit, dates map[string]git.CommitDates, rewritten map[string]string) {
+	path, err := cfg.AuditLogPath()
+	if err != nil || path == "" {
+		if err != nil {
+			logger.WarnContext(ctx, "unable to record rewritten commits in audit log", "error", err)
+		}
+
+		return
+	}
+
+	repository, err := git.RepositoryPath(ctx)
+	if err != nil {
+		logger.WarnContext(ctx, "unable to find repository path for audit log", "error", err)
+	}
+
+	var entries []audit.Entry
+
+	for _, commit := range commits {
+		newDates, found := dates[commit.Hash]
+		if !found {
+			continue
+		}
+
+		entries = append(entries, audit.Entry{
+			RewrittenAt:        time.Now(),
+			Repository:         repository,
+			Origin:             origin,
+			OldHash:            commit.Hash,
+			NewHash:            rewritten[commit.Hash],
+			AuthorDate:         commit.AuthorDate,
+			FakedAuthorDate:    newDates.Author.Truncate(time.Second),
+			CommitterDate:      commit.CommitterDate,
+			FakedCommitterDate: newDates.Committer.Truncate(time.Second),
+			Schedule:           cfg.Schedule,
+			InvertSchedule:     cfg.InvertSchedule,
+		})
+	}
+
+	if err := audit.Append(path, entries...); err != nil {
+		logger.WarnContext(ctx, "unable to record rewritten commits in audit log", "path", path, "error", err)
+		return
+	}
+
+	logger.DebugContext(ctx, "rewritten commits recorded in audit log", "path", path, "commits", len(entries))
+}
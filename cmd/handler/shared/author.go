@@ -0,0 +1,221 @@
+package handlershared
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+
+	sourcedefault "github.com/krostar/cli/cfg/source/default"
+
+	"github.com/krostar/git-workhours/internal/git"
+	gitconfig "github.com/krostar/git-workhours/internal/git/config"
+)
+
+// SelectNamedValues returns the name of the named git configuration values to apply: the name of the first
+// author schedule rule matching the author email, or the value of wh.use if no rule matches.
+// Rules of every wh.authorschedules value are considered, in order.
+func SelectNamedValues(ctx context.Context, values map[string][]string) (string, error) {
+	var rules []string
+	for _, value := range values["authorschedules"] {
+		rules = append(rules, strings.Split(value, ",")...)
+	}
+
+	var use string
+	if uses := values["use"]; len(uses) > 0 {
+		use = uses[len(uses)-1]
+	}
+
+	return selectNamedValues(ctx, rules, use)
+}
+
+// ForAuthor returns the configuration applying to commits of the author of the provided email, and whether the author
+// is bound to a schedule, see AuthorUnrestricted. When the author schedule rule matching the email selects other
+// named values than the ones of the current author, the configuration is rebuilt from the git configuration with
+// the other named values instead, keeping the fields set by the environment or flags.
+func (cfg Config) ForAuthor(ctx context.Context, email string) (Config, bool, error) {
+	if len(cfg.AuthorSchedules) == 0 {
+		return cfg, true, nil
+	}
+
+	name, matched, err := matchAuthorSchedule(cfg.AuthorSchedules, email)
+	if err != nil {
+		return Config{}, false, err
+	}
+
+	if !matched || name == "" {
+		return cfg, false, nil
+	}
+
+	current, err := selectNamedValues(ctx, cfg.AuthorSchedules, cfg.Use)
+	if err != nil {
+		return Config{}, false, err
+	}
+
+	if name == current {
+		return cfg, true, nil
+	}
+
+	var sourced, author Config
+
+	if err := sourceNamedConfig(ctx, &sourced, current); err != nil {
+		return Config{}, false, err
+	}
+
+	if err := sourceNamedConfig(ctx, &author, name); err != nil {
+		return Config{}, false, fmt.Errorf("unable to apply values selected for %s: %w", email, err)
+	}
+
+	// fields differing from the ones sourced from the git configuration were set by the environment or flags
+	sourcedValue, cfgValue, authorValue := reflect.ValueOf(sourced), reflect.ValueOf(cfg), reflect.ValueOf(&author).Elem()
+	for i := range cfgValue.NumField() {
+		if !reflect.DeepEqual(cfgValue.Field(i).Interface(), sourcedValue.Field(i).Interface()) {
+			authorValue.Field(i).Set(cfgValue.Field(i))
+		}
+	}
+
+	return author, true, nil
+}
+
+// AuthorCommits holds some commits, on which the same configuration applies.
+type AuthorCommits struct {
+	Config  Config
+	Commits []git.Commit
+}
+
+// GroupByAuthor groups the commits by the configuration applying to their author, see ForAuthor, overridden by the
+// policies of the branch if any, see ForBranch, with the schedule file resolved.
+// Commits keep their order within groups, commits of authors not bound to any schedule are left out.
+func (cfg Config) GroupByAuthor(ctx context.Context, branch string, commits []git.Commit) ([]AuthorCommits, error) {
+	type author struct {
+		cfg   Config
+		bound bool
+	}
+
+	var (
+		groups  []AuthorCommits
+		authors = make(map[string]author)
+	)
+
+	for _, commit := range commits {
+		a, found := authors[commit.AuthorEmail]
+		if !found {
+			var err error
+			if a.cfg, a.bound, err = cfg.ForAuthor(ctx, commit.AuthorEmail); err != nil {
+				return nil, fmt.Errorf("unable to match author schedules: %w", err)
+			}
+
+			if a.cfg, err = a.cfg.ForBranch(branch); err != nil {
+				return nil, err
+			}
+
+			if a.cfg, err = a.cfg.ResolveScheduleFile(); err != nil {
+				return nil, err
+			}
+
+			authors[commit.AuthorEmail] = a
+		}
+
+		if !a.bound {
+			continue
+		}
+
+		idx := slices.IndexFunc(groups, func(group AuthorCommits) bool { return reflect.DeepEqual(group.Config, a.cfg) })
+		if idx < 0 {
+			idx = len(groups)
+			groups = append(groups, AuthorCommits{Config: a.cfg})
+		}
+
+		groups[idx].Commits = append(groups[idx].Commits, commit)
+	}
+
+	return groups, nil
+}
+
+// AuthorUnrestricted returns whether commits of the current author escape any schedule, which is the case
+// when author schedule rules are set but none of them matches the author email, or the matching rule has no name.
+// Without author identity, rules don't apply and the author is restricted by the configuration.
+func (cfg Config) AuthorUnrestricted(ctx context.Context) (bool, string, error) {
+	if len(cfg.AuthorSchedules) == 0 {
+		return false, "", nil
+	}
+
+	email := authorEmail(ctx)
+	if email == "" {
+		return false, "", nil
+	}
+
+	name, matched, err := matchAuthorSchedule(cfg.AuthorSchedules, email)
+	if err != nil {
+		return false, "", err
+	}
+
+	return !matched || name == "", email, nil
+}
+
+// selectNamedValues returns the name of the first rule matching the current author email, or use if none matches.
+func selectNamedValues(ctx context.Context, rules []string, use string) (string, error) {
+	if email := authorEmail(ctx); len(rules) > 0 && email != "" {
+		name, matched, err := matchAuthorSchedule(rules, email)
+		if err != nil {
+			return "", err
+		}
+
+		if matched {
+			return name, nil
+		}
+	}
+
+	return use, nil
+}
+
+// sourceNamedConfig sources the configuration from its defaults and git configuration, applying the values named after name.
+func sourceNamedConfig(ctx context.Context, cfg *Config, name string) error {
+	if err := sourcedefault.Source[Config]()(ctx, cfg); err != nil {
+		return err
+	}
+
+	return gitconfig.Source[Config]("wh",
+		gitconfig.SourceWithIgnoreConfigError(),
+		gitconfig.SourceWithNamedSelectorFunc(func(context.Context, map[string][]string) (string, error) { return name, nil }),
+		gitconfig.SourceWithSubsectionFields("remote", "branch"),
+	)(ctx, cfg)
+}
+
+// authorEmail returns the email of the current author, or an empty string when git has no author identity,
+// like on CI runners without user.email, so that commands relying on the configuration still work.
+func authorEmail(ctx context.Context) string {
+	email, err := git.AuthorEmail(ctx)
+	if err != nil {
+		return ""
+	}
+
+	return email
+}
+
+// matchAuthorSchedule returns the name of the first rule, in the form '<email glob>=<name>', matching the email.
+func matchAuthorSchedule(rules []string, email string) (string, bool, error) {
+	for _, rule := range rules {
+		if rule = strings.TrimSpace(rule); rule == "" {
+			continue
+		}
+
+		pattern, name, found := strings.Cut(rule, "=")
+		if !found {
+			return "", false, fmt.Errorf("invalid author schedule %q, expected '<email glob>=<name>'", rule)
+		}
+
+		matched, err := path.Match(strings.ToLower(strings.TrimSpace(pattern)), strings.ToLower(email))
+		if err != nil {
+			return "", false, fmt.Errorf("invalid author schedule %q: %w", rule, err)
+		}
+
+		if matched {
+			return strings.TrimSpace(name), true, nil
+		}
+	}
+
+	return "", false, nil
+}
@@ -0,0 +1,176 @@
+package handlershared
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/krostar/cli"
+	clicfg "github.com/krostar/cli/cfg"
+	sourcedefault "github.com/krostar/cli/cfg/source/default"
+	sourceenv "github.com/krostar/cli/cfg/source/env"
+	sourceflag "github.com/krostar/cli/cfg/source/flag"
+
+	gitconfig "github.com/krostar/git-workhours/internal/git/config"
+	"github.com/krostar/git-workhours/internal/schedulefile"
+	"github.com/krostar/git-workhours/internal/workhours"
+)
+
+// Config holds the configuration shared by all commands evaluating the work schedule.
+type Config struct {
+	Schedule       string
+	Exceptions     []string
+	TimeOff        []string
+	Timezone       string
+	InvertSchedule bool
+	AllowOvertime  bool
+	MaskTimezone   string
+	// ScheduleFile is the path of a TOML, YAML, or JSON file defining the schedule, along with ScheduleProfile, the profile to use in it.
+	ScheduleFile    string
+	ScheduleProfile string
+	// AuditLog is the path of the log recording rewritten commits, 'off' disables it.
+	AuditLog string
+	// Use is the name of the named values to apply, like '[wh "schedule.client-a"]', it can only be set with git config.
+	Use string `env:"-"`
+	// AuthorSchedules selects named values by author email, with rules like '*@company.com=office', it can only be set with git config.
+	AuthorSchedules []string `env:"-"`
+	// Remote overrides fields when pushing to a remote, keyed by remote name, it can only be set with git config 'wh.remote.<name>.<field>'.
+	Remote map[string]map[string]string `env:"-"`
+	// Branch overrides fields on branches matching a pattern, it can only be set with git config 'wh.branch.<pattern>.<field>'.
+	Branch map[string]map[string]string `env:"-"`
+}
+
+// WorkSchedule builds the work schedule described by the configuration.
+func (cfg Config) WorkSchedule() (workhours.Schedule, error) {
+	cfg, err := cfg.ResolveScheduleFile()
+	if err != nil {
+		return workhours.Schedule{}, err
+	}
+
+	weekly, err := workhours.ParseWeeklySchedule(cfg.Schedule)
+	if err != nil {
+		return workhours.Schedule{}, err
+	}
+
+	schedule := workhours.Schedule{Weekly: weekly}
+
+	if cfg.Timezone != "" {
+		location, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return workhours.Schedule{}, fmt.Errorf("unable to load schedule timezone: %w", err)
+		}
+
+		schedule.Location = location
+	}
+
+	for _, raw := range cfg.Exceptions {
+		if raw == "" {
+			continue
+		}
+
+		exception, err := workhours.ParseScheduleException(raw)
+		if err != nil {
+			return workhours.Schedule{}, fmt.Errorf("unable to parse schedule exception: %w", err)
+		}
+
+		schedule.Exceptions = append(schedule.Exceptions, exception)
+	}
+
+	if cfg.InvertSchedule {
+		schedule = schedule.Inverted()
+	}
+
+	for _, raw := range cfg.TimeOff {
+		if raw == "" {
+			continue
+		}
+
+		timeOff, err := workhours.ParseDateRange(raw)
+		if err != nil {
+			return workhours.Schedule{}, fmt.Errorf("unable to parse time off: %w", err)
+		}
+
+		schedule.TimeOff = append(schedule.TimeOff, timeOff)
+	}
+
+	return schedule, nil
+}
+
+// ResolveScheduleFile returns the configuration with the values of the schedule file: its schedule and timezone only apply
+// when not set by flags, environment, or git configuration, its holidays, exceptions, and time off are added to the configured ones.
+// The returned configuration has no schedule file anymore, so that it isn't loaded again.
+func (cfg Config) ResolveScheduleFile() (Config, error) {
+	if cfg.ScheduleFile == "" {
+		return cfg, nil
+	}
+
+	file, err := schedulefile.Load(cfg.ScheduleFile)
+	if err != nil {
+		return Config{}, fmt.Errorf("unable to load schedule file: %w", err)
+	}
+
+	def, err := file.Profile(cfg.ScheduleProfile)
+	if err != nil {
+		return Config{}, fmt.Errorf("unable to load schedule file %s: %w", cfg.ScheduleFile, err)
+	}
+
+	if cfg.Schedule == "" {
+		cfg.Schedule = def.WeeklySchedule()
+	}
+
+	if cfg.Timezone == "" {
+		cfg.Timezone = def.Timezone
+	}
+
+	cfg.Exceptions = slices.Concat(cfg.Exceptions, def.Holidays, def.Exceptions)
+	cfg.TimeOff = slices.Concat(cfg.TimeOff, def.TimeOff)
+	cfg.ScheduleFile, cfg.ScheduleProfile = "", ""
+
+	return cfg, nil
+}
+
+// MaskLocation returns the fixed timezone rewritten commit dates should carry, or nil if commits should keep theirs.
+func (cfg Config) MaskLocation() (*time.Location, error) {
+	switch cfg.MaskTimezone {
+	case "":
+		return nil, nil //nolint:nilnil // no mask is not an error
+	case "UTC", "Z":
+		return time.UTC, nil
+	}
+
+	for _, layout := range []string{"-0700", "-07:00", "-07"} {
+		if t, err := time.Parse(layout, cfg.MaskTimezone); err == nil {
+			_, offset := t.Zone()
+			return time.FixedZone("", offset), nil
+		}
+	}
+
+	return nil, fmt.Errorf("unable to parse timezone mask %q, expected UTC or a fixed offset like +0200", cfg.MaskTimezone)
+}
+
+// Flags returns the flags setting the shared configuration.
+func Flags(cfg *Config) []cli.Flag {
+	return []cli.Flag{
+		cli.NewBuiltinFlag("schedule", "", &cfg.Schedule, "Work schedule in format 'slice of time shift', eg: ',8h-12h+13h-18h,9h-18h,,,,', or 'mon-fri 08:00-12:00,13:00-18:00'"),
+		cli.NewBuiltinFlag("schedule-file", "", &cfg.ScheduleFile, "Path of a TOML, YAML, or JSON file defining the schedule, used when the schedule or timezone aren't set otherwise, eg: '~/.config/git-workhours/schedule.toml'"),
+		cli.NewBuiltinFlag("schedule-profile", "", &cfg.ScheduleProfile, "Name of the profile to use in the schedule file"),
+		cli.NewBuiltinSliceFlag("schedule-exceptions", "", &cfg.Exceptions, "Dates or date ranges overriding the work schedule, eg: '2026-12-25,2026-12-24=9h-12h,2026-08-01..2026-08-15'"),
+		cli.NewBuiltinSliceFlag("time-off", "", &cfg.TimeOff, "Dates or date ranges during which no work is expected at all, eg: '2026-12-20..2027-01-03'"),
+		cli.NewBuiltinFlag("timezone", "", &cfg.Timezone, "IANA timezone in which the work schedule is evaluated, eg: 'Europe/Paris', defaults to the time's own timezone"),
+		cli.NewBuiltinFlag("inverse-schedule", "", &cfg.InvertSchedule, "Invert the work schedule"),
+		cli.NewBuiltinFlag("allow-overtime", "", &cfg.AllowOvertime, "Allow commits outside work hours with warning"),
+		cli.NewBuiltinFlag("mask-timezone", "", &cfg.MaskTimezone, "Rewrite all commit dates with this fixed timezone offset to hide your location, eg: 'UTC', '+0200'"),
+		cli.NewBuiltinFlag("audit-log", "", &cfg.AuditLog, "Path of the log recording the real dates of rewritten commits, 'off' to disable it, defaults to $XDG_STATE_HOME/git-workhours/audit.jsonl"),
+	}
+}
+
+// SourceConfigHook returns a hook sourcing the provided configuration from its defaults, git configuration, environment, and flags.
+func SourceConfigHook[T any](dest *T) func(context.Context) error {
+	return clicfg.BeforeCommandExecutionHook(dest,
+		sourcedefault.Source[T](),
+		gitconfig.Source[T]("wh", gitconfig.SourceWithIgnoreConfigError(), gitconfig.SourceWithNamedSelectorFunc(SelectNamedValues), gitconfig.SourceWithSubsectionFields("remote", "branch")),
+		sourceenv.Source[T]("WH"),
+		sourceflag.Source[T](dest),
+	)
+}
@@ -0,0 +1,12 @@
+package handlershared
+
+import (
+	"time"
+
+	"github.com/krostar/git-workhours/internal/workhours"
+)
+
+// FormatDuration formats durations shown to users, to the minute and without their trailing zero units, eg: '1h30m' or '2h'.
+func FormatDuration(d time.Duration) string {
+	return workhours.FormatDuration(d.Truncate(time.Minute))
+}
@@ -0,0 +1,185 @@
+package handlershared
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/krostar/git-workhours/internal/git"
+	"github.com/krostar/git-workhours/internal/workhours"
+)
+
+// FakeDateWithinShift generates a realistic date within the provided work shift boundaries
+// while preserving chronological order with the previous commit, and never going after upperLimit.
+//
+// Rules:
+// - starts with previous work shift time boundaries as the base window
+// - adjusts lower bound to previous commit time if it's after shift start (maintains chronological order)
+// - adjusts upper bound to previous commit time + 10min if previous commit is after shift end
+// - caps upper bound to upper limit (usually the current time) if upper bound is after it
+// - for tight windows (<45min): distributes randomly across available time
+// - for normal windows: adds realistic delay (5-15min base + 0-30min random) to simulate human timing
+func FakeDateWithinShift(shift *workhours.WorkingShift, previousCommitTime, upperLimit time.Time) time.Time {
+	lowerBound := shift[0]
+	upperBound := shift[1]
+
+	if previousCommitTime.After(lowerBound) {
+		lowerBound = previousCommitTime
+	}
+
+	if previousCommitTime.After(upperBound) {
+		upperBound = previousCommitTime.Add(time.Minute * 10)
+	}
+
+	if upperBound.After(upperLimit) {
+		upperBound = upperLimit
+	}
+
+	timeAvailable := upperBound.Sub(lowerBound)
+	if timeAvailable <= 0 {
+		return lowerBound
+	}
+
+	if timeAvailable < time.Minute*45 {
+		return lowerBound.Add(time.Duration(rand.Int64N(int64(timeAvailable))))
+	}
+
+	return lowerBound.Add(
+		(time.Minute * time.Duration(5+rand.Int64N(10))) + // 5-15mn
+			time.Duration(rand.Int64N(int64(30*time.Minute))), // 0-30mn
+	)
+}
+
+// CommitDatesFaker computes dates within work hours for commits made outside of them.
+type CommitDatesFaker struct {
+	Schedule workhours.Schedule
+	// Mask is the fixed timezone all dates are rewritten with, including the ones of commits made within work hours, if set.
+	Mask *time.Location
+	// UseScheduleTimezone makes faked dates carry the schedule timezone offset instead of the original one.
+	UseScheduleTimezone bool
+	// ForCommit returns the faker to use for a commit, like when commits of several authors follow different schedules,
+	// if set. Dates of commits for which it returns false are left as is.
+	ForCommit func(commit git.Commit) (CommitDatesFaker, bool)
+	Logger    *slog.Logger
+}
+
+// FakeDates returns the new dates of the provided commits, indexed by commit hash.
+// Commits must be sorted parents first, dates are faked commit by commit, using FakeDateWithinShift,
+// so that author and committer dates keep increasing starting from the dates of the first commit's parent.
+// Commits whose dates don't need to change are not part of the result, neither are commits whose dates can't be faked
+// within work hours, like when the previous commit leaves no time in the shift, which are logged.
+func (f CommitDatesFaker) FakeDates(ctx context.Context, commits []git.Commit) (map[string]git.CommitDates, error) {
+	dates := make(map[string]git.CommitDates)
+
+	if len(commits) == 0 {
+		return dates, nil
+	}
+
+	var previousCommitTime, previousCommitterTime time.Time
+	if parents := commits[0].Parents; len(parents) > 0 {
+		parent, err := git.GetCommit(ctx, parents[0])
+		if err != nil {
+			return nil, fmt.Errorf("could not get first commit parent: %w", err)
+		}
+
+		previousCommitTime, previousCommitterTime = parent.AuthorDate, parent.CommitterDate
+	}
+
+	for _, commit := range commits {
+		faker, fake := f, true
+		if f.ForCommit != nil {
+			faker, fake = f.ForCommit(commit)
+			faker.Logger = f.Logger
+		}
+
+		authorDate, committerDate := commit.AuthorDate, commit.CommitterDate
+
+		if fake {
+			if fakedDates, changed := faker.fakeDates(ctx, commit, previousCommitTime, previousCommitterTime); changed {
+				dates[commit.Hash] = fakedDates
+				authorDate, committerDate = fakedDates.Author, fakedDates.Committer
+			}
+		}
+
+		if authorDate.After(previousCommitTime) {
+			previousCommitTime = authorDate
+		}
+
+		if committerDate.After(previousCommitterTime) {
+			previousCommitterTime = committerDate
+		}
+	}
+
+	return dates, nil
+}
+
+// fakeDates returns the new dates of the commit, made after the provided previous commit dates, if they need to change.
+func (f CommitDatesFaker) fakeDates(ctx context.Context, commit git.Commit, previousCommitTime, previousCommitterTime time.Time) (git.CommitDates, bool) {
+	authorShift := f.Schedule.CurrentShift(commit.AuthorDate)
+
+	switch {
+	case authorShift == nil:
+		previousShift := f.Schedule.PreviousShift(commit.AuthorDate)
+		if previousShift == nil {
+			f.Logger.WarnContext(ctx, "commit is over time but no previous shift found, leaving it as is", "commit", commit.Hash)
+			return git.CommitDates{}, false
+		}
+
+		probableTime := FakeDateWithinShift(previousShift, previousCommitTime, commit.AuthorDate)
+		if !probableTime.After(previousCommitTime) {
+			probableTime = previousCommitTime.Add(time.Second)
+		}
+
+		if f.Schedule.CurrentShift(probableTime) == nil {
+			f.Logger.WarnContext(ctx, "commit is over time but no time is left within work hours after the previous commit, leaving it as is",
+				"commit", commit.Hash,
+				"shift", previousShift.String(),
+			)
+
+			return git.CommitDates{}, false
+		}
+
+		if !f.UseScheduleTimezone {
+			probableTime = probableTime.In(commit.AuthorDate.Location())
+		}
+
+		if f.Mask != nil {
+			probableTime = probableTime.In(f.Mask)
+		}
+
+		f.Logger.InfoContext(ctx, "changing commit date to avoid overtime",
+			"commit", commit.Hash,
+			"shift", previousShift.String(),
+			"old", commit.AuthorDate.Format(time.DateTime),
+			"new", probableTime.Format(time.DateTime),
+		)
+
+		return git.CommitDates{Author: probableTime, Committer: probableTime}, true
+
+	case f.Schedule.CurrentShift(commit.CommitterDate) == nil:
+		// the commit was authored within work hours but committed after, like when rebasing late: it is committed
+		// when it was authored, or when the previous commit was committed if it is later within the same shift
+		authorDate, committerDate := commit.AuthorDate, commit.AuthorDate
+		if previousCommitterTime.After(committerDate) && previousCommitterTime.Before(authorShift[1]) {
+			committerDate = previousCommitterTime
+		}
+
+		committerDate = committerDate.In(commit.CommitterDate.Location())
+		if f.Mask != nil {
+			authorDate, committerDate = authorDate.In(f.Mask), committerDate.In(f.Mask)
+		}
+
+		return git.CommitDates{Author: authorDate, Committer: committerDate}, true
+
+	case f.Mask != nil:
+		maskedAuthorDate, maskedCommitterDate := commit.AuthorDate.In(f.Mask), commit.CommitterDate.In(f.Mask)
+
+		if maskedAuthorDate.Format(time.RFC3339) != commit.AuthorDate.Format(time.RFC3339) || maskedCommitterDate.Format(time.RFC3339) != commit.CommitterDate.Format(time.RFC3339) {
+			return git.CommitDates{Author: maskedAuthorDate, Committer: maskedCommitterDate}, true
+		}
+	}
+
+	return git.CommitDates{}, false
+}
@@ -0,0 +1,132 @@
+package handlershared
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/krostar/test"
+	"github.com/krostar/test/check"
+
+	"github.com/krostar/git-workhours/internal/git"
+	"github.com/krostar/git-workhours/internal/workhours"
+)
+
+func Test_CommitDatesFaker_FakeDates(t *testing.T) {
+	weekly, err := workhours.ParseWeeklySchedule(",9h-18h,9h-18h,9h-18h,9h-18h,9h-18h,")
+	test.Require(t, err == nil, err)
+
+	plus2 := time.FixedZone("", 2*3600)
+	monday := func(hour, minute int, loc *time.Location) time.Time {
+		return time.Date(2026, 10, 12, hour, minute, 0, 0, time.UTC).In(loc)
+	}
+
+	for name, tc := range map[string]struct {
+		mask          *time.Location
+		leftAsIs      []string
+		commits       []git.Commit
+		expectedDates map[string][2]string
+	}{
+		"commits within work hours are left as is": {
+			commits: []git.Commit{
+				{Hash: "a", AuthorDate: monday(10, 0, time.UTC), CommitterDate: monday(10, 0, time.UTC)},
+				{Hash: "b", AuthorDate: monday(11, 0, time.UTC), CommitterDate: monday(12, 0, plus2)},
+			},
+			expectedDates: map[string][2]string{},
+		},
+		"no time left in the previous shift after the previous commit": {
+			commits: []git.Commit{
+				{Hash: "a", AuthorDate: monday(18, 0, time.UTC).Add(-time.Nanosecond), CommitterDate: monday(17, 0, time.UTC)},
+				{Hash: "b", AuthorDate: monday(22, 0, time.UTC), CommitterDate: monday(22, 0, time.UTC)},
+			},
+			expectedDates: map[string][2]string{},
+		},
+		"late rebase of several commits": {
+			commits: []git.Commit{
+				{Hash: "a", AuthorDate: monday(10, 0, time.UTC), CommitterDate: monday(22, 0, time.UTC)},
+				{Hash: "b", AuthorDate: monday(9, 30, time.UTC), CommitterDate: monday(22, 0, time.UTC)},
+				{Hash: "c", AuthorDate: monday(11, 0, time.UTC), CommitterDate: monday(22, 0, plus2)},
+			},
+			expectedDates: map[string][2]string{
+				"a": {"2026-10-12T10:00:00Z", "2026-10-12T10:00:00Z"},
+				"b": {"2026-10-12T09:30:00Z", "2026-10-12T10:00:00Z"},
+				"c": {"2026-10-12T11:00:00Z", "2026-10-12T13:00:00+02:00"},
+			},
+		},
+		"late rebase after a commit of a previous shift": {
+			commits: []git.Commit{
+				{Hash: "a", AuthorDate: monday(10, 0, time.UTC), CommitterDate: monday(10, 0, time.UTC)},
+				{Hash: "b", AuthorDate: monday(9, 30, time.UTC).AddDate(0, 0, 1), CommitterDate: monday(22, 0, time.UTC).AddDate(0, 0, 1)},
+			},
+			expectedDates: map[string][2]string{
+				"b": {"2026-10-13T09:30:00Z", "2026-10-13T09:30:00Z"},
+			},
+		},
+		"mask applied to commits within work hours": {
+			mask: plus2,
+			commits: []git.Commit{
+				{Hash: "a", AuthorDate: monday(10, 0, plus2), CommitterDate: monday(10, 0, plus2)},
+				{Hash: "b", AuthorDate: monday(11, 0, plus2), CommitterDate: monday(12, 0, time.UTC)},
+				{Hash: "c", AuthorDate: monday(13, 0, time.UTC), CommitterDate: monday(13, 0, plus2)},
+			},
+			expectedDates: map[string][2]string{
+				"b": {"2026-10-12T13:00:00+02:00", "2026-10-12T14:00:00+02:00"},
+				"c": {"2026-10-12T15:00:00+02:00", "2026-10-12T15:00:00+02:00"},
+			},
+		},
+		"late rebase with mask": {
+			mask: plus2,
+			commits: []git.Commit{
+				{Hash: "a", AuthorDate: monday(10, 0, time.UTC), CommitterDate: monday(22, 0, time.UTC)},
+			},
+			expectedDates: map[string][2]string{
+				"a": {"2026-10-12T12:00:00+02:00", "2026-10-12T12:00:00+02:00"},
+			},
+		},
+		"commits left as is keep the following ones ordered": {
+			leftAsIs: []string{"b"},
+			commits: []git.Commit{
+				{Hash: "a", AuthorDate: monday(17, 0, time.UTC), CommitterDate: monday(17, 0, time.UTC)},
+				{Hash: "b", AuthorDate: monday(20, 0, time.UTC), CommitterDate: monday(20, 0, time.UTC)},
+				{Hash: "c", AuthorDate: monday(21, 0, time.UTC), CommitterDate: monday(21, 0, time.UTC)},
+			},
+			expectedDates: map[string][2]string{},
+		},
+		"commits left as is among faked ones": {
+			leftAsIs: []string{"b"},
+			commits: []git.Commit{
+				{Hash: "a", AuthorDate: monday(10, 0, time.UTC), CommitterDate: monday(22, 0, time.UTC)},
+				{Hash: "b", AuthorDate: monday(11, 0, time.UTC), CommitterDate: monday(22, 0, time.UTC)},
+				{Hash: "c", AuthorDate: monday(12, 0, time.UTC), CommitterDate: monday(22, 0, time.UTC)},
+			},
+			expectedDates: map[string][2]string{
+				"a": {"2026-10-12T10:00:00Z", "2026-10-12T10:00:00Z"},
+				"c": {"2026-10-12T12:00:00Z", "2026-10-12T12:00:00Z"},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			faker := CommitDatesFaker{
+				Schedule: workhours.Schedule{Weekly: weekly},
+				Mask:     tc.mask,
+				Logger:   slog.New(slog.DiscardHandler),
+			}
+
+			dates, err := CommitDatesFaker{
+				ForCommit: func(commit git.Commit) (CommitDatesFaker, bool) {
+					return faker, !slices.Contains(tc.leftAsIs, commit.Hash)
+				},
+				Logger: faker.Logger,
+			}.FakeDates(t.Context(), tc.commits)
+			test.Require(t, err == nil, err)
+
+			formatted := make(map[string][2]string, len(dates))
+			for hash, d := range dates {
+				formatted[hash] = [2]string{d.Author.Format(time.RFC3339), d.Committer.Format(time.RFC3339)}
+			}
+
+			test.Assert(check.Compare(t, formatted, tc.expectedDates))
+		})
+	}
+}
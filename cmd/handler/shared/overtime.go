@@ -0,0 +1,78 @@
+package handlershared
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/krostar/git-workhours/internal/git"
+	"github.com/krostar/git-workhours/internal/workhours"
+)
+
+// OvertimeCommit describes a commit whose author or committer date falls outside of the schedule.
+type OvertimeCommit struct {
+	Commit             git.Commit
+	AuthorOvertime     bool
+	CommitterOvertime  bool
+	PreviousShiftEnded *time.Time
+}
+
+// FindOvertimeCommits returns the commits whose author or committer date falls outside of the schedule.
+func FindOvertimeCommits(schedule workhours.Schedule, commits []git.Commit) []OvertimeCommit {
+	var overtime []OvertimeCommit
+
+	for _, commit := range commits {
+		oc := OvertimeCommit{
+			Commit:            commit,
+			AuthorOvertime:    schedule.CurrentShift(commit.AuthorDate) == nil,
+			CommitterOvertime: schedule.CurrentShift(commit.CommitterDate) == nil,
+		}
+
+		if !oc.AuthorOvertime && !oc.CommitterOvertime {
+			continue
+		}
+
+		if previous := schedule.PreviousShift(commit.AuthorDate); previous != nil && oc.AuthorOvertime {
+			oc.PreviousShiftEnded = &previous[1]
+		}
+
+		overtime = append(overtime, oc)
+	}
+
+	return overtime
+}
+
+// String returns a single line describing why the commit is over time.
+func (oc OvertimeCommit) String() string {
+	var reasons []string
+
+	if oc.AuthorOvertime {
+		reason := "authored " + oc.Commit.AuthorDate.Format("2006-01-02 15:04:05 -0700")
+		if oc.PreviousShiftEnded != nil {
+			reason += fmt.Sprintf(" (%s after previous shift ended)", FormatDuration(oc.Commit.AuthorDate.Sub(*oc.PreviousShiftEnded)))
+		}
+
+		reasons = append(reasons, reason)
+	}
+
+	if oc.CommitterOvertime {
+		reasons = append(reasons, "committed "+oc.Commit.CommitterDate.Format("2006-01-02 15:04:05 -0700"))
+	}
+
+	hash := oc.Commit.Hash
+	if len(hash) > 12 {
+		hash = hash[:12]
+	}
+
+	return hash + " " + strings.Join(reasons, ", ") + " outside of work hours"
+}
+
+// FormatOvertimeCommits returns a multi-line report of the provided over time commits.
+func FormatOvertimeCommits(overtime []OvertimeCommit) string {
+	lines := make([]string, 0, len(overtime))
+	for _, oc := range overtime {
+		lines = append(lines, "  "+oc.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,90 @@
+package handlershared
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/krostar/git-workhours/internal/git"
+	"github.com/krostar/git-workhours/internal/git/config/reflectx"
+)
+
+// ForRemote returns the configuration overridden by the 'wh.remote.<name>.<field>' values of the remote.
+func (cfg Config) ForRemote(remote string) (Config, error) {
+	if remote == "" {
+		return cfg, nil
+	}
+
+	return cfg.withOverrides("remote "+remote, cfg.Remote[remote])
+}
+
+// ForBranch returns the configuration overridden by the 'wh.branch.<pattern>.<field>' values
+// of each pattern matching the branch, applied in pattern order.
+func (cfg Config) ForBranch(branch string) (Config, error) {
+	patterns, err := cfg.BranchPatterns(branch)
+	if err != nil {
+		return Config{}, err
+	}
+
+	overridden := cfg
+
+	for _, pattern := range patterns {
+		if overridden, err = overridden.withOverrides("branch "+pattern, cfg.Branch[pattern]); err != nil {
+			return Config{}, err
+		}
+	}
+
+	return overridden, nil
+}
+
+// BranchPatterns returns the patterns of the branch policies matching the branch, in the order they apply.
+func (cfg Config) BranchPatterns(branch string) ([]string, error) {
+	if branch == "" {
+		return nil, nil
+	}
+
+	var patterns []string
+
+	for _, pattern := range slices.Sorted(maps.Keys(cfg.Branch)) {
+		matched, err := path.Match(pattern, branch)
+		if err != nil {
+			return nil, fmt.Errorf("invalid branch pattern %q: %w", pattern, err)
+		}
+
+		if matched {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return patterns, nil
+}
+
+// ForCurrentBranch returns the configuration overridden for the checked out branch, see ForBranch.
+func (cfg Config) ForCurrentBranch(ctx context.Context) (Config, error) {
+	branch, err := git.CurrentBranch(ctx)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return cfg.ForBranch(branch)
+}
+
+func (cfg Config) withOverrides(policy string, overrides map[string]string) (Config, error) {
+	overridden := cfg
+
+	for _, key := range slices.Sorted(maps.Keys(overrides)) {
+		field, _, _ := strings.Cut(key, ".")
+		if slices.Contains([]string{"remote", "branch", "use", "authorschedules"}, strings.ToLower(field)) {
+			return Config{}, fmt.Errorf("%s: %s can't be overridden per remote or branch", policy, field)
+		}
+
+		if err := reflectx.SetToPath(&overridden, key, overrides[key]); err != nil {
+			return Config{}, fmt.Errorf("%s: unable to override %s: %w", policy, key, err)
+		}
+	}
+
+	return overridden, nil
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/krostar/cli"
+	clidi "github.com/krostar/cli/di"
+	"github.com/mattn/go-isatty"
+
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
+	"github.com/krostar/git-workhours/internal/stats"
+)
+
+// Stats returns the command showing when commits were made compared to the work schedule.
+func Stats() cli.Command { return new(cmdStats) }
+
+type cmdStats struct {
+	cfg    cmdStatsConfig
+	logger *slog.Logger
+}
+
+type cmdStatsConfig struct {
+	handlershared.Config `env:"^"`
+
+	Authors []string
+	Since   string
+	Until   string
+	Top     int
+	Color   string
+	Output  string
+}
+
+func (cfg *cmdStatsConfig) SetDefault() {
+	cfg.Top = 5
+	cfg.Color = "auto"
+	cfg.Output = "text"
+}
+
+func (*cmdStats) Description() string {
+	return "Show a weekday by hour heatmap of the commits of a revision range, defaulting to HEAD, " +
+		"along with the amount of commits made over time and on weekends, and the weeks with the most overtime commits."
+}
+
+func (*cmdStats) Usage() string {
+	return "[<revision range>]"
+}
+
+func (cmd *cmdStats) Flags() []cli.Flag {
+	return append(handlershared.Flags(&cmd.cfg.Config),
+		cli.NewBuiltinSliceFlag("authors", "", &cmd.cfg.Authors, "Only count commits authored with these emails, defaults to all authors"),
+		cli.NewBuiltinFlag("since", "", &cmd.cfg.Since, "Only count commits really authored after this date, in any format git understands, eg: '2026-01-01', '3 months ago'"),
+		cli.NewBuiltinFlag("until", "", &cmd.cfg.Until, "Only count commits really authored before this date, in any format git understands"),
+		cli.NewBuiltinFlag("top", "", &cmd.cfg.Top, "Amount of weeks with the most overtime commits to show"),
+		cli.NewBuiltinFlag("color", "", &cmd.cfg.Color, "Color the heatmap (auto, always, never)"),
+		cli.NewBuiltinFlag("output", "o", &cmd.cfg.Output, "Output format (text, json)"),
+	)
+}
+
+func (cmd *cmdStats) Hook() *cli.Hook {
+	return &cli.Hook{
+		BeforeCommandExecution: func(ctx context.Context) error {
+			if err := clidi.Invoke(ctx, func(logger *slog.Logger) { cmd.logger = logger.With("cmd", "stats") }); err != nil {
+				return err
+			}
+
+			return handlershared.SourceConfigHook(&cmd.cfg)(ctx)
+		},
+	}
+}
+
+// statsReport is the machine-readable representation of the statistics.
+type statsReport struct {
+	Commits   int               `json:"commits"`
+	Overtime  int               `json:"overtime_commits"`
+	Weekend   int               `json:"weekend_commits"`
+	Heatmap   stats.Heatmap     `json:"heatmap"`
+	Scheduled [7][24]bool       `json:"scheduled"`
+	Weeks     []statsReportWeek `json:"worst_weeks"`
+}
+
+// statsReportWeek is the machine-readable representation of a week of the statistics.
+type statsReportWeek struct {
+	Week     string `json:"week"`
+	Commits  int    `json:"commits"`
+	Overtime int    `json:"overtime_commits"`
+}
+
+func (cmd *cmdStats) Execute(ctx context.Context, args, _ []string) error {
+	if len(args) > 1 {
+		return cli.NewErrorWithHelp(fmt.Errorf("expected at most one revision range, got %d arguments", len(args)))
+	}
+
+	if cmd.cfg.Output != "text" && cmd.cfg.Output != "json" {
+		return cli.NewErrorWithHelp(fmt.Errorf("unknown output format %q", cmd.cfg.Output))
+	}
+
+	var color bool
+
+	switch cmd.cfg.Color {
+	case "auto":
+		color = isatty.IsTerminal(os.Stdout.Fd()) && os.Getenv("NO_COLOR") == ""
+	case "always":
+		color = true
+	case "never":
+	default:
+		return cli.NewErrorWithHelp(fmt.Errorf("unknown color mode %q", cmd.cfg.Color))
+	}
+
+	revisionRange := "HEAD"
+	if len(args) == 1 {
+		revisionRange = args[0]
+	}
+
+	schedule, err := cmd.cfg.WorkSchedule()
+	if err != nil {
+		return fmt.Errorf("unable to parse schedule: %w", err)
+	}
+
+	commits, err := listRealCommits(ctx, cmd.logger, cmd.cfg.Config, revisionRange, realCommitsFilter{
+		authors: cmd.cfg.Authors,
+		since:   cmd.cfg.Since,
+		until:   cmd.cfg.Until,
+	})
+	if err != nil {
+		return err
+	}
+
+	dates := make([]time.Time, len(commits))
+	for i, commit := range commits {
+		dates[i] = commit.AuthorDate
+	}
+
+	computed := stats.Compute(schedule, dates)
+	report := statsReport{
+		Commits:   computed.Commits,
+		Overtime:  computed.Overtime,
+		Weekend:   computed.Weekend,
+		Heatmap:   computed.Heatmap,
+		Scheduled: stats.ScheduledSlots(schedule.Weekly),
+		Weeks:     []statsReportWeek{},
+	}
+
+	for _, week := range computed.Weeks[:min(max(cmd.cfg.Top, 0), len(computed.Weeks))] {
+		if week.Overtime == 0 {
+			break
+		}
+
+		report.Weeks = append(report.Weeks, statsReportWeek{Week: fmt.Sprintf("%04d-W%02d", week.Year, week.Week), Commits: week.Commits, Overtime: week.Overtime})
+	}
+
+	if cmd.cfg.Output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(report)
+	}
+
+	fmt.Print(report.text(color))
+
+	return nil
+}
+
+func (r statsReport) text(color bool) string {
+	const (
+		colorScheduled = "\x1b[42;30m"
+		colorOvertime  = "\x1b[41;97m"
+		colorReset     = "\x1b[0m"
+	)
+
+	// cells are sized to the largest count, with room for the over time mark and a separating space
+	width := 2
+	for _, hours := range r.Heatmap {
+		for _, commits := range hours {
+			width = max(width, len(strconv.Itoa(commits))+1)
+		}
+	}
+
+	width++
+
+	var b strings.Builder
+
+	b.WriteString("    ")
+
+	for hour := range 24 {
+		fmt.Fprintf(&b, "%*d", width, hour)
+	}
+
+	b.WriteString("\n")
+
+	// weeks start on monday
+	for _, day := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday} {
+		b.WriteString(day.String()[:3] + " ")
+
+		for hour, commits := range r.Heatmap[day] {
+			cell := fmt.Sprintf("%*s", width, ".")
+			if commits > 0 {
+				cell = fmt.Sprintf("%*d", width, commits)
+			}
+
+			switch {
+			case color && r.Scheduled[day][hour]:
+				cell = colorScheduled + cell + colorReset
+			case color && commits > 0:
+				cell = colorOvertime + cell + colorReset
+			case !color && !r.Scheduled[day][hour] && commits > 0:
+				cell = fmt.Sprintf("%*d*", width-1, commits)
+			}
+
+			b.WriteString(cell)
+		}
+
+		b.WriteString("\n")
+	}
+
+	if color {
+		box := strings.Repeat(" ", width)
+		b.WriteString("\n" + colorScheduled + box + colorReset + " within work hours  " + colorOvertime + box + colorReset + " over time\n")
+	} else {
+		b.WriteString("\n* over time\n")
+	}
+
+	percent := func(count int) float64 {
+		if r.Commits == 0 {
+			return 0
+		}
+
+		return float64(count) * 100 / float64(r.Commits)
+	}
+
+	fmt.Fprintf(&b, "\ncommits: %d\n", r.Commits)
+	fmt.Fprintf(&b, "overtime commits: %d (%.1f%%)\n", r.Overtime, percent(r.Overtime))
+	fmt.Fprintf(&b, "weekend commits: %d (%.1f%%)\n", r.Weekend, percent(r.Weekend))
+
+	if len(r.Weeks) > 0 {
+		b.WriteString("\nweeks with the most overtime commits:\n")
+
+		for _, week := range r.Weeks {
+			fmt.Fprintf(&b, "  %s: %d overtime commits out of %d\n", week.Week, week.Overtime, week.Commits)
+		}
+	}
+
+	return b.String()
+}
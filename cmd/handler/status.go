@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/krostar/cli"
+
+	handlershared "github.com/krostar/git-workhours/cmd/handler/shared"
+	"github.com/krostar/git-workhours/internal/git"
+	"github.com/krostar/git-workhours/internal/workhours"
+)
+
+// Status returns the command showing whether a time is within work hours.
+func Status() cli.Command { return new(cmdStatus) }
+
+type cmdStatus struct {
+	cfg cmdStatusConfig
+}
+
+type cmdStatusConfig struct {
+	handlershared.Config `env:"^"`
+
+	At     string
+	Output string
+}
+
+func (cfg *cmdStatusConfig) SetDefault() {
+	cfg.Output = "text"
+}
+
+func (*cmdStatus) Description() string {
+	return "Show whether now, or the provided date, is within work hours, with the current, previous, and next shifts."
+}
+
+func (cmd *cmdStatus) Flags() []cli.Flag {
+	return append(handlershared.Flags(&cmd.cfg.Config),
+		cli.NewBuiltinFlag("at", "", &cmd.cfg.At, "Date to check instead of now, in any format git understands, eg: '2026-10-16 21:00', 'yesterday 18:00'"),
+		cli.NewBuiltinFlag("output", "o", &cmd.cfg.Output, "Output format (text, short, json), short is meant for shell prompts"),
+	)
+}
+
+func (cmd *cmdStatus) Hook() *cli.Hook {
+	return &cli.Hook{BeforeCommandExecution: handlershared.SourceConfigHook(&cmd.cfg)}
+}
+
+// statusShift is the machine-readable representation of a shift.
+type statusShift struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// statusReport is the machine-readable representation of the status.
+type statusReport struct {
+	At            time.Time    `json:"at"`
+	WithinShift   bool         `json:"within_shift"`
+	CurrentShift  *statusShift `json:"current_shift,omitempty"`
+	RemainingSecs *int64       `json:"remaining_seconds,omitempty"`
+	PreviousShift *statusShift `json:"previous_shift,omitempty"`
+	NextShift     *statusShift `json:"next_shift,omitempty"`
+	NextShiftSecs *int64       `json:"next_shift_in_seconds,omitempty"`
+}
+
+func (cmd *cmdStatus) Execute(ctx context.Context, _, _ []string) error {
+	schedule, err := cmd.cfg.WorkSchedule()
+	if err != nil {
+		return fmt.Errorf("unable to parse schedule: %w", err)
+	}
+
+	at := time.Now()
+	if cmd.cfg.At != "" {
+		if at, err = git.ResolveDate(ctx, cmd.cfg.At); err != nil {
+			return fmt.Errorf("could not resolve date: %w", err)
+		}
+	}
+
+	report := newStatusReport(schedule, at)
+
+	switch cmd.cfg.Output {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(report)
+	case "short":
+		fmt.Println(report.short())
+	case "text":
+		fmt.Println(report.text())
+	default:
+		return cli.NewErrorWithHelp(fmt.Errorf("unknown output format %q", cmd.cfg.Output))
+	}
+
+	return nil
+}
+
+func newStatusReport(schedule workhours.Schedule, at time.Time) statusReport {
+	report := statusReport{At: at}
+
+	toStatusShift := func(shift *workhours.WorkingShift) *statusShift {
+		if shift == nil {
+			return nil
+		}
+
+		return &statusShift{Start: shift[0], End: shift[1]}
+	}
+
+	if current := schedule.CurrentShift(at); current != nil {
+		remaining := int64(current[1].Sub(at).Seconds())
+		report.WithinShift, report.CurrentShift, report.RemainingSecs = true, toStatusShift(current), &remaining
+	} else {
+		report.PreviousShift = toStatusShift(schedule.PreviousShift(at))
+	}
+
+	if next := schedule.NextShift(at); next != nil {
+		until := int64(next[0].Sub(at).Seconds())
+		report.NextShift, report.NextShiftSecs = toStatusShift(next), &until
+	}
+
+	return report
+}
+
+func (r statusReport) text() string {
+	const layout = "Mon 2006-01-02 15:04"
+
+	if r.WithinShift {
+		return fmt.Sprintf("within work hours, current shift ends %s, %s remaining", r.CurrentShift.End.Format(layout), handlershared.FormatDuration(time.Duration(*r.RemainingSecs)*time.Second))
+	}
+
+	text := "over time"
+
+	if r.PreviousShift != nil {
+		text += fmt.Sprintf(", previous shift ended %s, %s ago", r.PreviousShift.End.Format(layout), handlershared.FormatDuration(r.At.Sub(r.PreviousShift.End)))
+	}
+
+	if r.NextShift != nil {
+		text += fmt.Sprintf(", next shift starts %s, in %s", r.NextShift.Start.Format(layout), handlershared.FormatDuration(time.Duration(*r.NextShiftSecs)*time.Second))
+	} else {
+		text += ", no next shift within a week"
+	}
+
+	return text
+}
+
+func (r statusReport) short() string {
+	if r.WithinShift {
+		return "work " + handlershared.FormatDuration(time.Duration(*r.RemainingSecs)*time.Second)
+	}
+
+	if r.NextShift != nil {
+		return "off " + handlershared.FormatDuration(time.Duration(*r.NextShiftSecs)*time.Second)
+	}
+
+	return "off"
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/krostar/cli"
+
+	"github.com/krostar/git-workhours/internal/git"
+)
+
+// Uninstall returns the command removing hook shims written by the install command.
+func Uninstall() cli.Command { return new(cmdUninstall) }
+
+type cmdUninstall struct {
+	cfg cmdUninstallConfig
+}
+
+type cmdUninstallConfig struct {
+	Global bool
+	Hooks  []string
+	DryRun bool
+}
+
+func (*cmdUninstall) Description() string {
+	return "Remove git-workhours hooks from the current repository, or from the global hooks directory, restoring the hooks they replaced."
+}
+
+func (cmd *cmdUninstall) Flags() []cli.Flag {
+	return []cli.Flag{
+		cli.NewBuiltinFlag("global", "", &cmd.cfg.Global, "Uninstall hooks from the global hooks directory set by core.hooksPath"),
+		cli.NewBuiltinSliceFlag("hooks", "", &cmd.cfg.Hooks, "Hooks to uninstall, eg: 'pre-commit,post-commit,pre-push'"),
+		cli.NewBuiltinFlag("dry-run", "", &cmd.cfg.DryRun, "Only print what would change"),
+	}
+}
+
+func (cmd *cmdUninstall) Execute(ctx context.Context, _, _ []string) error {
+	dir, _, err := hooksDirectory(ctx, cmd.cfg.Global)
+	if err != nil {
+		return err
+	}
+
+	var actions []fileAction
+
+	hooks := hooksOrDefault(cmd.cfg.Hooks)
+
+	for _, hook := range hooks {
+		path := filepath.Join(dir, hook)
+
+		if !isHookShim(path) {
+			actions = append(actions, fileAction{description: path + " is not installed"})
+			continue
+		}
+
+		actions = append(actions, fileAction{
+			description: "remove hook " + path,
+			apply:       func() error { return os.Remove(path) },
+		})
+
+		if _, err := os.Stat(path + hookBackupSuffix); err == nil {
+			actions = append(actions, fileAction{
+				description: fmt.Sprintf("restore previous hook %s to %s", path+hookBackupSuffix, path),
+				apply:       func() error { return os.Rename(path+hookBackupSuffix, path) },
+			})
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("unable to check previous hook: %w", err)
+		}
+	}
+
+	if cmd.cfg.Global {
+		remaining, err := remainingHookShims(dir, hooks)
+		if err != nil {
+			return err
+		}
+
+		installedHooksPath, err := git.GetConfigPath(ctx, git.ConfigScopeGlobal, "wh.installedHooksPath")
+		if err != nil {
+			return err
+		}
+
+		// core.hooksPath is only unset when no hook shim is left, and if it was set by the install command
+		if len(remaining) == 0 && installedHooksPath != "" && installedHooksPath == dir {
+			actions = append(actions, fileAction{
+				description: "unset global core.hooksPath",
+				apply: func() error {
+					return errors.Join(
+						git.UnsetConfig(ctx, git.ConfigScopeGlobal, "core.hooksPath"),
+						git.UnsetConfig(ctx, git.ConfigScopeGlobal, "wh.installedHooksPath"),
+					)
+				},
+			})
+		}
+	}
+
+	return applyFileActions(actions, cmd.cfg.DryRun)
+}
+
+// remainingHookShims returns the names of the hook shims of the directory which are not about to be uninstalled.
+func remainingHookShims(dir string, uninstalled []string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("unable to read hooks directory: %w", err)
+	}
+
+	var remaining []string
+
+	for _, entry := range entries {
+		if !entry.IsDir() && !slices.Contains(uninstalled, entry.Name()) && isHookShim(filepath.Join(dir, entry.Name())) {
+			remaining = append(remaining, entry.Name())
+		}
+	}
+
+	return remaining, nil
+}
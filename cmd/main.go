@@ -20,10 +20,21 @@ func main() {
 
 func buildCLI() *cli.CLI {
 	return cli.New(handler.Root()).
+		AddCommand("check", handler.Check()).
+		AddCommand("rewrite", handler.Rewrite()).
+		AddCommand("install", handler.Install()).
+		AddCommand("uninstall", handler.Uninstall()).
+		AddCommand("doctor", handler.Doctor()).
+		AddCommand("status", handler.Status()).
+		AddCommand("audit", handler.Audit()).
+		AddCommand("report", handler.Report()).
+		AddCommand("stats", handler.Stats()).
 		Mount("hooks", cli.New(handlerhooks.Root()).
 			AddCommand("print-config", handlerhooks.PrintConfig()).
 			AddCommand("pre-commit", handlerhooks.PreCommit()).
 			AddCommand("post-commit", handlerhooks.PostCommit()).
-			AddCommand("pre-push", handlerhooks.PrePush()),
+			AddCommand("pre-push", handlerhooks.PrePush()).
+			AddCommand("pre-receive", handlerhooks.PreReceive()).
+			AddCommand("update", handlerhooks.Update()),
 		)
 }
@@ -8,7 +8,9 @@ require (
 	github.com/krostar/cli v1.6.1
 	github.com/krostar/test v1.0.1
 	github.com/mattn/go-isatty v0.0.20
+	github.com/pelletier/go-toml/v2 v2.4.3
 	gitlab.com/greyxor/slogor v1.6.3
+	go.yaml.in/yaml/v3 v3.0.4
 )
 
 require (
@@ -0,0 +1,176 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Entry records the rewrite of a single commit.
+type Entry struct {
+	// RewrittenAt is when the commit has been rewritten.
+	RewrittenAt time.Time `json:"rewritten_at"`
+	// Repository is the path of the repository the commit belongs to.
+	Repository string `json:"repository"`
+	// Origin is what rewrote the commit, like 'post-commit', 'pre-push', or 'rewrite'.
+	Origin  string `json:"origin"`
+	OldHash string `json:"old_hash"`
+	NewHash string `json:"new_hash"`
+
+	AuthorDate         time.Time `json:"author_date"`
+	FakedAuthorDate    time.Time `json:"faked_author_date"`
+	CommitterDate      time.Time `json:"committer_date"`
+	FakedCommitterDate time.Time `json:"faked_committer_date"`
+
+	// Schedule is the raw schedule used to fake dates.
+	Schedule       string `json:"schedule"`
+	InvertSchedule bool   `json:"invert_schedule,omitempty"`
+}
+
+// DefaultPath returns the default path of the audit log, in $XDG_STATE_HOME/git-workhours/.
+func DefaultPath() (string, error) {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("unable to get home directory: %w", err)
+		}
+
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+
+	return filepath.Join(stateHome, "git-workhours", "audit.jsonl"), nil
+}
+
+// Append appends the entries to the audit log, one JSON object per line,
+// creating the file and its parent directories if needed.
+func Append(path string, entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("unable to create audit log directory: %w", err)
+	}
+
+	var content strings.Builder
+
+	for _, entry := range entries {
+		raw, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("unable to encode audit entry: %w", err)
+		}
+
+		content.Write(raw)
+		content.WriteByte('\n')
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600) //nolint:gosec // path is provided by the user
+	if err != nil {
+		return fmt.Errorf("unable to open audit log: %w", err)
+	}
+
+	_, err = file.WriteString(content.String())
+
+	return errors.Join(err, file.Close())
+}
+
+// Read returns the entries of the audit log, oldest first, a missing audit log having no entries.
+func Read(path string) ([]Entry, error) {
+	file, err := os.Open(path) //nolint:gosec // path is provided by the user
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to open audit log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var entries []Entry
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid audit entry: %w", path, line, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// RealAuthorDates returns the real author dates of rewritten commits, indexed by their latest hash.
+// Entries must be sorted oldest first, commits rewritten several times are followed back to their first rewrite.
+func RealAuthorDates(entries []Entry) map[string]time.Time {
+	dates := make(map[string]time.Time, len(entries))
+
+	for _, entry := range entries {
+		date, found := dates[entry.OldHash]
+		if !found {
+			date = entry.AuthorDate
+		}
+
+		dates[entry.NewHash] = date
+	}
+
+	return dates
+}
+
+// Filter selects audit entries, zero fields don't filter anything.
+type Filter struct {
+	// Repository only keeps entries of the repository at this path.
+	Repository string
+	// Commit only keeps entries whose old or new hash starts with it.
+	Commit string
+	// Since and Until only keep entries whose real author date is within them.
+	Since time.Time
+	Until time.Time
+}
+
+// Match returns whether the entry is selected by the filter.
+func (f Filter) Match(entry Entry) bool {
+	switch {
+	case f.Repository != "" && filepath.Clean(f.Repository) != filepath.Clean(entry.Repository):
+		return false
+	case f.Commit != "" && !strings.HasPrefix(entry.OldHash, f.Commit) && !strings.HasPrefix(entry.NewHash, f.Commit):
+		return false
+	case !f.Since.IsZero() && entry.AuthorDate.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && entry.AuthorDate.After(f.Until):
+		return false
+	default:
+		return true
+	}
+}
+
+// Apply returns the entries selected by the filter.
+func (f Filter) Apply(entries []Entry) []Entry {
+	var selected []Entry
+
+	for _, entry := range entries {
+		if f.Match(entry) {
+			selected = append(selected, entry)
+		}
+	}
+
+	return selected
+}
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krostar/test"
+	"github.com/krostar/test/check"
+)
+
+func Test_DefaultPath(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/state")
+
+	path, err := DefaultPath()
+	test.Require(t, err == nil, err)
+	test.Assert(check.Compare(t, path, "/state/git-workhours/audit.jsonl"))
+
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("HOME", "/home/bob")
+
+	path, err = DefaultPath()
+	test.Require(t, err == nil, err)
+	test.Assert(check.Compare(t, path, "/home/bob/.local/state/git-workhours/audit.jsonl"))
+}
+
+func Test_Append_Read(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "audit.jsonl")
+
+	entries, err := Read(path)
+	test.Require(t, err == nil, err)
+	test.Assert(t, len(entries) == 0)
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	test.Require(t, err == nil, err)
+
+	first := Entry{
+		RewrittenAt:        time.Date(2026, 10, 16, 21, 0, 0, 0, paris),
+		Repository:         "/src/project",
+		Origin:             "post-commit",
+		OldHash:            "1111111111111111111111111111111111111111",
+		NewHash:            "2222222222222222222222222222222222222222",
+		AuthorDate:         time.Date(2026, 10, 16, 21, 0, 0, 0, paris),
+		FakedAuthorDate:    time.Date(2026, 10, 16, 17, 42, 0, 0, time.UTC),
+		CommitterDate:      time.Date(2026, 10, 16, 21, 0, 0, 0, paris),
+		FakedCommitterDate: time.Date(2026, 10, 16, 17, 42, 0, 0, time.UTC),
+		Schedule:           ",9h-18h,9h-18h,9h-18h,9h-18h,9h-18h,",
+	}
+	second := first
+	second.Origin, second.OldHash, second.NewHash = "rewrite", "3333333333333333333333333333333333333333", "4444444444444444444444444444444444444444"
+
+	test.Require(t, Append(path) == nil)
+	test.Require(t, Append(path, first) == nil)
+	test.Require(t, Append(path, second) == nil)
+
+	entries, err = Read(path)
+	test.Require(t, err == nil, err)
+	test.Require(t, len(entries) == 2)
+	test.Assert(check.Compare(t, entries[0].OldHash, first.OldHash))
+	test.Assert(check.Compare(t, entries[1].Origin, "rewrite"))
+	test.Assert(t, entries[0].AuthorDate.Equal(first.AuthorDate))
+	test.Assert(check.Compare(t, entries[0].AuthorDate.Format(time.RFC3339), "2026-10-16T21:00:00+02:00"))
+
+	info, err := os.Stat(path)
+	test.Require(t, err == nil, err)
+	test.Assert(t, info.Mode().Perm() == 0o600)
+
+	test.Require(t, os.WriteFile(path, []byte("{}\nnot json\n"), 0o600) == nil)
+
+	_, err = Read(path)
+	test.Assert(t, err != nil && strings.Contains(err.Error(), "audit.jsonl:2: invalid audit entry"), err)
+}
+
+func Test_Filter(t *testing.T) {
+	entries := []Entry{
+		{Repository: "/src/a", OldHash: "aaaa1111", NewHash: "bbbb1111", AuthorDate: time.Date(2026, 10, 1, 21, 0, 0, 0, time.UTC)},
+		{Repository: "/src/a", OldHash: "aaaa2222", NewHash: "bbbb2222", AuthorDate: time.Date(2026, 10, 10, 21, 0, 0, 0, time.UTC)},
+		{Repository: "/src/b", OldHash: "cccc3333", NewHash: "dddd3333", AuthorDate: time.Date(2026, 10, 20, 21, 0, 0, 0, time.UTC)},
+	}
+
+	for name, tc := range map[string]struct {
+		filter   Filter
+		expected []Entry
+	}{
+		"no filter":     {filter: Filter{}, expected: entries},
+		"repository":    {filter: Filter{Repository: "/src/a/"}, expected: entries[:2]},
+		"old hash":      {filter: Filter{Commit: "aaaa2"}, expected: entries[1:2]},
+		"new hash":      {filter: Filter{Commit: "dddd"}, expected: entries[2:]},
+		"since":         {filter: Filter{Since: time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC)}, expected: entries[1:]},
+		"until":         {filter: Filter{Until: time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC)}, expected: entries[:1]},
+		"combined":      {filter: Filter{Repository: "/src/a", Since: time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)}, expected: entries[1:2]},
+		"none selected": {filter: Filter{Repository: "/src/c"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			test.Assert(check.Compare(t, tc.filter.Apply(entries), tc.expected))
+		})
+	}
+}
+
+func Test_RealAuthorDates(t *testing.T) {
+	first, second := time.Date(2026, 10, 1, 21, 0, 0, 0, time.UTC), time.Date(2026, 10, 2, 22, 0, 0, 0, time.UTC)
+
+	dates := RealAuthorDates([]Entry{
+		{OldHash: "a1", NewHash: "a2", AuthorDate: first},
+		{OldHash: "b1", NewHash: "b2", AuthorDate: second},
+		{OldHash: "a2", NewHash: "a3", AuthorDate: time.Date(2026, 10, 1, 17, 0, 0, 0, time.UTC)},
+	})
+
+	test.Assert(check.Compare(t, dates, map[string]time.Time{"a2": first, "a3": first, "b2": second}))
+}
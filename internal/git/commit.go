@@ -39,9 +39,83 @@ func GetCommitTime(ctx context.Context, revision string, skip int) (time.Time, e
 	return time.Unix(timestamp, 0), nil
 }
 
-// AmendLastCommitDate modifies the date of the last commit to the specified time.
-func AmendLastCommitDate(ctx context.Context, date time.Time) error {
-	rawDate := date.Format("Mon, 02 Jan 2006 15:04:05 -0700")
+// Commit holds the metadata of a git commit.
+type Commit struct {
+	Hash           string
+	Parents        []string
+	AuthorEmail    string
+	AuthorDate     time.Time
+	CommitterEmail string
+	CommitterDate  time.Time
+}
+
+// GetCommit retrieves the metadata of a specific git revision.
+func GetCommit(ctx context.Context, revision string) (Commit, error) {
+	commits, err := ListCommits(ctx, "--max-count=1", revision)
+	if err != nil {
+		return Commit{}, err
+	}
+
+	if len(commits) == 0 {
+		return Commit{}, fmt.Errorf("no commit found for revision %q", revision)
+	}
+
+	return commits[0], nil
+}
+
+// ListCommits lists the metadata of the commits returned by git log for the provided arguments.
+func ListCommits(ctx context.Context, args ...string) ([]Commit, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"log", "--format=%H%x00%P%x00%ae%x00%aI%x00%ce%x00%cI"}, args...)...)
+
+	output, err := cmd.Output()
+	if err != nil {
+		var stdErr string
+		if exitErr := (*exec.ExitError)(nil); errors.As(err, &exitErr) {
+			stdErr = "; stderr: " + string(exitErr.Stderr)
+		}
+
+		return nil, fmt.Errorf("unable to list commits: %w%s", err, stdErr)
+	}
+
+	return parseCommits(string(output))
+}
+
+func parseCommits(raw string) ([]Commit, error) {
+	var commits []Commit
+
+	for line := range strings.SplitSeq(strings.TrimSpace(raw), "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("unable to parse commit %q: expected 6 fields, got %d", line, len(fields))
+		}
+
+		authorDate, errAuthorDate := time.Parse(time.RFC3339, fields[3])
+		committerDate, errCommitterDate := time.Parse(time.RFC3339, fields[5])
+
+		if err := errors.Join(errAuthorDate, errCommitterDate); err != nil {
+			return nil, fmt.Errorf("unable to parse dates of commit %s: %w", fields[0], err)
+		}
+
+		commits = append(commits, Commit{
+			Hash:           fields[0],
+			Parents:        strings.Fields(fields[1]),
+			AuthorEmail:    fields[2],
+			AuthorDate:     authorDate,
+			CommitterEmail: fields[4],
+			CommitterDate:  committerDate,
+		})
+	}
+
+	return commits, nil
+}
+
+// AmendLastCommitDate modifies the author and committer dates of the last commit to the specified times.
+func AmendLastCommitDate(ctx context.Context, authorDate, committerDate time.Time) error {
+	rawAuthorDate := formatDate(authorDate)
 
 	var cmdEnv []string
 	{
@@ -64,14 +138,15 @@ func AmendLastCommitDate(ctx context.Context, date time.Time) error {
 			}
 		}
 
-		envs["GIT_COMMITTER_DATE"] = rawDate
+		envs["GIT_COMMITTER_DATE"] = formatDate(committerDate)
 
 		for k, v := range envs {
 			cmdEnv = append(cmdEnv, fmt.Sprintf("%s=%s", k, v))
 		}
 	}
 
-	cmdArgs := []string{"commit", "--amend", "--no-edit", "--date", rawDate}
+	// hooks already ran for the commit being amended, --no-verify skips pre-commit and commit-msg but not post-commit
+	cmdArgs := []string{"-c", "core.hooksPath=" + os.DevNull, "commit", "--amend", "--no-edit", "--no-verify", "--date", rawAuthorDate}
 
 	git := exec.CommandContext(ctx, "git", cmdArgs...)
 	git.Env = cmdEnv
@@ -82,3 +157,8 @@ func AmendLastCommitDate(ctx context.Context, date time.Time) error {
 
 	return nil
 }
+
+// formatDate formats a date the way git expects it, keeping its timezone offset.
+func formatDate(date time.Time) string {
+	return date.Format("Mon, 02 Jan 2006 15:04:05 -0700")
+}
@@ -0,0 +1,71 @@
+package git
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krostar/test"
+	"github.com/krostar/test/check"
+)
+
+func Test_parseCommits(t *testing.T) {
+	for name, tc := range map[string]struct {
+		raw                string
+		expected           []Commit
+		expectErrorMessage string
+	}{
+		"empty": {
+			raw: "\n",
+		},
+		"ok": {
+			raw: "abc\x00def ghi\x00a@b.c\x002020-03-27T22:00:23+02:00\x00c@d.e\x002020-03-27T22:01:00Z\n" +
+				"def\x00\x00a@b.c\x002020-03-26T10:00:00-05:30\x00a@b.c\x002020-03-26T10:00:00-05:30\n",
+			expected: []Commit{
+				{
+					Hash:           "abc",
+					Parents:        []string{"def", "ghi"},
+					AuthorEmail:    "a@b.c",
+					AuthorDate:     time.Date(2020, time.March, 27, 22, 0, 23, 0, time.FixedZone("", 2*60*60)),
+					CommitterEmail: "c@d.e",
+					CommitterDate:  time.Date(2020, time.March, 27, 22, 1, 0, 0, time.UTC),
+				},
+				{
+					Hash:           "def",
+					Parents:        []string{},
+					AuthorEmail:    "a@b.c",
+					AuthorDate:     time.Date(2020, time.March, 26, 10, 0, 0, 0, time.FixedZone("", -(5*60+30)*60)),
+					CommitterEmail: "a@b.c",
+					CommitterDate:  time.Date(2020, time.March, 26, 10, 0, 0, 0, time.FixedZone("", -(5*60+30)*60)),
+				},
+			},
+		},
+		"missing fields": {
+			raw:                "abc\x00def",
+			expectErrorMessage: "expected 6 fields, got 2",
+		},
+		"invalid date": {
+			raw:                "abc\x00\x00a@b.c\x00yesterday\x00a@b.c\x002020-03-26T10:00:00Z",
+			expectErrorMessage: "unable to parse dates of commit abc",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			commits, err := parseCommits(tc.raw)
+			if tc.expectErrorMessage == "" {
+				test.Require(t, err == nil, err)
+				test.Require(t, len(commits) == len(tc.expected))
+
+				for i := range commits {
+					test.Assert(t, commits[i].AuthorDate.Equal(tc.expected[i].AuthorDate) && commits[i].CommitterDate.Equal(tc.expected[i].CommitterDate))
+					test.Assert(t, commits[i].AuthorDate.Format(time.RFC3339) == tc.expected[i].AuthorDate.Format(time.RFC3339))
+					commits[i].AuthorDate, commits[i].CommitterDate = time.Time{}, time.Time{}
+					tc.expected[i].AuthorDate, tc.expected[i].CommitterDate = time.Time{}, time.Time{}
+				}
+
+				test.Assert(check.Compare(t, commits, tc.expected))
+			} else {
+				test.Require(t, err != nil && strings.Contains(err.Error(), tc.expectErrorMessage), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package gitconfig
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Origin describes where a git configuration value comes from.
+type Origin struct {
+	// Key is the configuration key without its section name.
+	Key   string
+	Value string
+	// Type is the type of origin as reported by git, like 'file', 'command line', or 'blob'.
+	Type string
+	// File is the absolute path of the configuration file, if the origin is a file.
+	File string
+	// Line is the line of the value in the configuration file, if it could be found.
+	Line int
+}
+
+// String returns a human-readable representation of the origin, like 'file:/home/bob/.gitconfig:12'.
+func (o Origin) String() string {
+	switch {
+	case o.File != "" && o.Line > 0:
+		return fmt.Sprintf("%s:%s:%d", o.Type, o.File, o.Line)
+	case o.File != "":
+		return o.Type + ":" + o.File
+	default:
+		return o.Type
+	}
+}
+
+// Origins returns the origin of each git configuration value of the section, in the order git applies them.
+// Git doesn't report lines, they are found by parsing configuration files.
+func Origins(ctx context.Context, sectionName string, opts ...SourceOption) ([]Origin, error) {
+	o := newSourceOptions(opts...)
+
+	gitArgs, err := o.gitConfigArgs("--show-origin", "--null", "--get-regexp", fmt.Sprintf("^%s\\..*", sectionName))
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := o.gitCommandExecutor(ctx, gitArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute git config command: %w", err)
+	}
+
+	origins, err := parseOrigins(output, sectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := make(map[[2]string]int)
+
+	for i, origin := range origins {
+		if origin.File == "" {
+			continue
+		}
+
+		id := [2]string{origin.File, strings.ToLower(origin.Key)}
+		occurrences[id]++
+		origins[i].Line = findConfigLine(origin.File, sectionName, origin.Key, occurrences[id])
+	}
+
+	return origins, nil
+}
+
+// ResolveNamedOrigins applies named values the way SourceWithNamedSelector does: origins of the values named after
+// the selected name are moved last with the key of their field, as they take precedence, other named values are dropped.
+func ResolveNamedOrigins(origins []Origin, selected string) []Origin {
+	resolved := make([]Origin, 0, len(origins))
+
+	var named []Origin
+
+	for _, origin := range origins {
+		field, name, isNamed := splitNamedKey(origin.Key)
+		if !isNamed {
+			resolved = append(resolved, origin)
+			continue
+		}
+
+		if name == selected {
+			origin.Key = field
+			named = append(named, origin)
+		}
+	}
+
+	return append(resolved, named...)
+}
+
+// parseOrigins parses the output of 'git config --show-origin --null --get-regexp',
+// made of 'origin\0key\nvalue\0' records.
+func parseOrigins(output, sectionName string) ([]Origin, error) {
+	fields := strings.Split(output, "\x00")
+
+	var origins []Origin
+
+	for i := 0; i+1 < len(fields); i += 2 {
+		rawOrigin, rawConfig := fields[i], fields[i+1]
+
+		originType, originPath, _ := strings.Cut(rawOrigin, ":")
+
+		key, value, _ := strings.Cut(rawConfig, "\n")
+		if key == "" {
+			return nil, fmt.Errorf("could not parse git config output: %q", rawConfig)
+		}
+
+		origin := Origin{
+			Key:   strings.TrimPrefix(key, sectionName+"."),
+			Value: value,
+			Type:  originType,
+		}
+
+		if originType == "file" && originPath != "" {
+			if abs, err := filepath.Abs(originPath); err == nil {
+				originPath = abs
+			}
+
+			origin.File = originPath
+		}
+
+		origins = append(origins, origin)
+	}
+
+	return origins, nil
+}
+
+// findConfigLine returns the line of the nth occurrence of the key in the section of the configuration file, or 0 if not found.
+func findConfigLine(path, sectionName, key string, occurrence int) int {
+	file, err := os.Open(path) //nolint:gosec // path is provided by git
+	if err != nil {
+		return 0
+	}
+	defer func() { _ = file.Close() }()
+
+	fullKey := sectionName + "." + key
+	keySection, keyName := fullKey[:strings.LastIndex(fullKey, ".")+1], fullKey[strings.LastIndex(fullKey, ".")+1:]
+
+	var (
+		inSection bool
+		found     int
+	)
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		content := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(content, "[") {
+			header, rest, _ := strings.Cut(strings.TrimPrefix(content, "["), "]")
+			inSection = strings.EqualFold(configSectionKey(header), keySection)
+
+			content = strings.TrimSpace(rest)
+			if content == "" {
+				continue
+			}
+		}
+
+		if !inSection {
+			continue
+		}
+
+		name, _, _ := strings.Cut(content, "=")
+		if strings.EqualFold(strings.TrimSpace(name), keyName) {
+			found++
+			if found == occurrence {
+				return line
+			}
+		}
+	}
+
+	return 0
+}
+
+// configSectionKey returns the dotted prefix of the keys defined under a section header,
+// like 'wh.' for '[wh]', and 'wh.sub.' for '[wh "sub"]' or '[wh.sub]'.
+func configSectionKey(header string) string {
+	section, subsection, hasSubsection := strings.Cut(strings.TrimSpace(header), " ")
+	if !hasSubsection {
+		return section + "."
+	}
+
+	return section + "." + strings.Trim(strings.TrimSpace(subsection), `"`) + "."
+}
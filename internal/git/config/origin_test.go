@@ -0,0 +1,87 @@
+package gitconfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/krostar/test"
+	"github.com/krostar/test/check"
+)
+
+func Test_Origins(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config")
+	test.Require(t, os.WriteFile(configFile, []byte(`[core]
+	bare = false
+[wh]
+	schedule = ,9h-17h,9h-17h,9h-17h,9h-17h,9h-17h,
+	exceptions = 2026-12-25
+[wh "sub"]
+	schedule = ,8h-12h,,,,,
+[WH]
+	Exceptions = 2027-01-01
+	allowovertime
+`), 0o600) == nil)
+
+	var gitArgs []string
+
+	origins, err := Origins(t.Context(), "wh",
+		SourceWithGitConfigParameterEnvName("TEST_GIT_CONFIG_PARAMETERS_NOT_SET"),
+		SourceWithGitCommandExecutor(func(_ context.Context, args ...string) (string, error) {
+			gitArgs = args
+			return "file:" + configFile + "\x00wh.schedule\n,9h-17h,9h-17h,9h-17h,9h-17h,9h-17h,\x00" +
+				"file:" + configFile + "\x00wh.exceptions\n2026-12-25\x00" +
+				"file:" + configFile + "\x00wh.sub.schedule\n,8h-12h,,,,,\x00" +
+				"file:" + configFile + "\x00wh.exceptions\n2027-01-01\x00" +
+				"file:" + configFile + "\x00wh.allowovertime\ntrue\x00" +
+				"command line:\x00wh.timezone\nEurope/Paris\x00", nil
+		}),
+	)
+	test.Require(t, err == nil, err)
+	test.Assert(check.Compare(t, gitArgs, []string{"config", "--show-origin", "--null", "--get-regexp", `^wh\..*`}))
+	test.Assert(check.Compare(t, origins, []Origin{
+		{Key: "schedule", Value: ",9h-17h,9h-17h,9h-17h,9h-17h,9h-17h,", Type: "file", File: configFile, Line: 4},
+		{Key: "exceptions", Value: "2026-12-25", Type: "file", File: configFile, Line: 5},
+		{Key: "sub.schedule", Value: ",8h-12h,,,,,", Type: "file", File: configFile, Line: 7},
+		{Key: "exceptions", Value: "2027-01-01", Type: "file", File: configFile, Line: 9},
+		{Key: "allowovertime", Value: "true", Type: "file", File: configFile, Line: 10},
+		{Key: "timezone", Value: "Europe/Paris", Type: "command line"},
+	}))
+
+	test.Assert(t, origins[0].String() == "file:"+configFile+":4")
+	test.Assert(t, origins[5].String() == "command line")
+}
+
+func Test_parseOrigins(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		origins, err := parseOrigins("", "wh")
+		test.Require(t, err == nil, err)
+		test.Assert(t, len(origins) == 0)
+	})
+
+	t.Run("ko", func(t *testing.T) {
+		_, err := parseOrigins("command line:\x00\x00", "wh")
+		test.Assert(t, err != nil)
+	})
+}
+
+func Test_ResolveNamedOrigins(t *testing.T) {
+	origins := []Origin{
+		{Key: "schedule.client-a.value", Value: "9h-12h", Type: "file"},
+		{Key: "schedule", Value: "9h-18h", Type: "file"},
+		{Key: "use", Value: "client-a", Type: "file"},
+		{Key: "schedule.client-b.value", Value: "14h-18h", Type: "file"},
+	}
+
+	test.Assert(check.Compare(t, ResolveNamedOrigins(origins, "client-a"), []Origin{
+		{Key: "schedule", Value: "9h-18h", Type: "file"},
+		{Key: "use", Value: "client-a", Type: "file"},
+		{Key: "schedule", Value: "9h-12h", Type: "file"},
+	}))
+
+	test.Assert(check.Compare(t, ResolveNamedOrigins(origins, ""), []Origin{
+		{Key: "schedule", Value: "9h-18h", Type: "file"},
+		{Key: "use", Value: "client-a", Type: "file"},
+	}))
+}
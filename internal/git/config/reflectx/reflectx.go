@@ -284,15 +284,13 @@ func (pw *pathWalker) walkInMap(v reflect.Value, path []string) (reflect.Value,
 		}
 	}
 
-	pv, err := pw.walk(mapValueValue, path[1:])
-	if err == nil {
-		pw.wo = append(pw.wo, func() error {
-			v.SetMapIndex(mapKeyValue, mapValueValue)
-			return nil
-		})
-	}
+	// registered before walking deeper so that it runs after write operations on the value, like nested maps creation
+	pw.wo = append(pw.wo, func() error {
+		v.SetMapIndex(mapKeyValue, mapValueValue)
+		return nil
+	})
 
-	return pv, err
+	return pw.walk(mapValueValue, path[1:])
 }
 
 func (pw *pathWalker) walkInSlice(v reflect.Value, path []string) (reflect.Value, error) {
@@ -202,6 +202,16 @@ func Test_SetToPath(t *testing.T) {
 		test.Require(t, err != nil && strings.Contains(err.Error(), `unable to set field value to path "foo.bar": unable to parse bool value "42"`), err)
 		test.Assert(t, dst.Foo == nil)
 	})
+
+	t.Run("path found in nested maps", func(t *testing.T) {
+		var dst struct {
+			Foo map[string]map[string]string
+		}
+
+		test.Require(t, SetToPath(&dst, "foo.bar.baz", "42") == nil)
+		test.Require(t, SetToPath(&dst, "foo.bar.qux", "21") == nil)
+		test.Assert(check.Compare(t, dst.Foo, map[string]map[string]string{"bar": {"baz": "42", "qux": "21"}}))
+	})
 }
 
 func Test_pathWalker_applyWriteOperations(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"reflect"
 	"strings"
 
 	clicfg "github.com/krostar/cli/cfg"
@@ -20,6 +21,8 @@ type sourceOptions struct {
 	gitCommandExecutor    func(context.Context, ...string) (string, error)
 	gitConfigParamEnvName string
 	ignoreSetFieldError   bool
+	namedSelector         func(ctx context.Context, values map[string][]string) (string, error)
+	subsectionFields      []string
 }
 
 // SourceWithGitCommandExecutor sets a custom git command executor.
@@ -37,8 +40,263 @@ func SourceWithIgnoreConfigError() SourceOption {
 	return func(o *sourceOptions) { o.ignoreSetFieldError = true }
 }
 
+// SourceWithNamedSelector enables named values, set with keys of the form '<section>.<field>.<name>.value',
+// like '[wh "schedule.client-a"] value = ...'. Named values whose name is the value of '<section>.<key>' are applied
+// to their field, taking precedence over the field's own value, other named values are ignored.
+// If '<section>.<key>' is set multiple times, the last value is used.
+func SourceWithNamedSelector(key string) SourceOption {
+	return SourceWithNamedSelectorFunc(func(_ context.Context, values map[string][]string) (string, error) {
+		if selected := values[strings.ToLower(key)]; len(selected) > 0 {
+			return selected[len(selected)-1], nil
+		}
+
+		return "", nil
+	})
+}
+
+// SourceWithNamedSelectorFunc enables named values like SourceWithNamedSelector does,
+// the name of the values to apply is returned by the selector, provided with every value of the other keys of the section.
+func SourceWithNamedSelectorFunc(selector func(ctx context.Context, values map[string][]string) (string, error)) SourceOption {
+	return func(o *sourceOptions) { o.namedSelector = selector }
+}
+
+// SourceWithSubsectionFields makes the provided fields, of type map[string]map[string]string, hold the values
+// of keys of the form '<section>.<field>.<name>.<key>', like '[wh "branch.release-1.2.*"] allowovertime = true'.
+// As git subsections, names can contain dots: keys are cut at their first and last dots.
+func SourceWithSubsectionFields(fields ...string) SourceOption {
+	return func(o *sourceOptions) { o.subsectionFields = append(o.subsectionFields, fields...) }
+}
+
 // Source creates a configuration source that loads git config values into a struct.
 func Source[T any](sectionName string, opts ...SourceOption) clicfg.SourceFunc[T] {
+	o := newSourceOptions(opts...)
+
+	return func(ctx context.Context, cfg *T) error {
+		values, named, err := o.sectionValues(ctx, sectionName, o.namedSelector != nil)
+		if err != nil {
+			return err
+		}
+
+		selected, err := o.selectNamed(ctx, values)
+		if err != nil {
+			return err
+		}
+
+		values = joinRepeatedSliceValues[T](values)
+
+		if selected != "" {
+			selectedValues := namedValues(named, selected)
+			if len(selectedValues) == 0 {
+				return fmt.Errorf("values named %q are selected but none is set, set them with '[%s \"<field>.%s\"] value = ...'", selected, sectionName, selected)
+			}
+
+			values = append(values, joinRepeatedSliceValues[T](selectedValues)...)
+		}
+
+		return applyValues(o, cfg, values)
+	}
+}
+
+// ApplyNamed applies the named values of the provided name, see SourceWithNamedSelector, to the configuration,
+// and returns whether any value has that name.
+func ApplyNamed[T any](ctx context.Context, cfg *T, sectionName, name string, opts ...SourceOption) (bool, error) {
+	o := newSourceOptions(opts...)
+
+	_, named, err := o.sectionValues(ctx, sectionName, true)
+	if err != nil {
+		return false, err
+	}
+
+	values := namedValues(named, name)
+	if len(values) == 0 {
+		return false, nil
+	}
+
+	return true, applyValues(o, cfg, joinRepeatedSliceValues[T](values))
+}
+
+// sectionValues returns the values of the section, named values being returned apart if splitNamed is set.
+func (o sourceOptions) sectionValues(ctx context.Context, sectionName string, splitNamed bool) ([]sourceValue, []sourceValue, error) {
+	gitArgs, err := o.gitConfigArgs("--get-regexp", fmt.Sprintf("^%s\\..*", sectionName))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output, err := o.gitCommandExecutor(ctx, gitArgs...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to execute git config command: %w", err)
+	}
+
+	var values, named []sourceValue
+
+	for _, config := range strings.Split(strings.TrimSpace(output), "\n") {
+		config = strings.TrimSpace(config)
+		if config == "" {
+			continue
+		}
+
+		parts := strings.SplitN(config, " ", 2)
+		if len(parts) < 2 {
+			return nil, nil, fmt.Errorf("could not parse git config output: %s", config)
+		}
+
+		value := sourceValue{raw: config, path: strings.TrimPrefix(parts[0], sectionName+"."), value: parts[1]}
+
+		if field, name, isNamed := splitNamedKey(value.path); isNamed && splitNamed {
+			value.path, value.name = field, name
+			named = append(named, value)
+
+			continue
+		}
+
+		values = append(values, value)
+	}
+
+	return values, named, nil
+}
+
+func applyValues[T any](o sourceOptions, cfg *T, values []sourceValue) error {
+	for _, value := range values {
+		var err error
+
+		if field, isSubsection := o.subsectionField(value.path); isSubsection {
+			err = setSubsectionValue(cfg, field, value.path, value.value)
+		} else {
+			err = reflectx.SetToPath(cfg, value.path, value.value)
+		}
+
+		if err != nil && !o.ignoreSetFieldError {
+			return fmt.Errorf("unable to apply git config %q: %w", value.raw, err)
+		}
+	}
+
+	return nil
+}
+
+type sourceValue struct {
+	raw   string
+	path  string
+	name  string
+	value string
+}
+
+// selectNamed returns the name of the named values to apply, if named values are enabled.
+func (o sourceOptions) selectNamed(ctx context.Context, values []sourceValue) (string, error) {
+	if o.namedSelector == nil {
+		return "", nil
+	}
+
+	byPath := make(map[string][]string, len(values))
+	for _, value := range values {
+		path := strings.ToLower(value.path)
+		byPath[path] = append(byPath[path], value.value)
+	}
+
+	selected, err := o.namedSelector(ctx, byPath)
+	if err != nil {
+		return "", fmt.Errorf("unable to select named values: %w", err)
+	}
+
+	return selected, nil
+}
+
+// subsectionField returns the subsection field the path belongs to, if any.
+func (o sourceOptions) subsectionField(path string) (string, bool) {
+	for _, field := range o.subsectionFields {
+		if len(path) > len(field) && strings.EqualFold(path[:len(field)+1], field+".") {
+			return field, true
+		}
+	}
+
+	return "", false
+}
+
+// setSubsectionValue sets the value of a path of the form '<field>.<name>.<key>', whose name can contain dots.
+func setSubsectionValue[T any](cfg *T, field, path, value string) error {
+	rest := path[len(field)+1:]
+
+	idx := strings.LastIndex(rest, ".")
+	if idx <= 0 || idx == len(rest)-1 {
+		return fmt.Errorf("expected a key of the form '%s.<name>.<key>', got %q", field, path)
+	}
+
+	name, key := rest[:idx], rest[idx+1:]
+
+	fieldValue, applyWriteOperations, err := reflectx.WalkToPath(cfg, field)
+	if err != nil {
+		return err
+	}
+
+	if fieldValue.Type() != reflect.TypeFor[map[string]map[string]string]() {
+		return fmt.Errorf("field %s of type %s can't hold subsections", field, fieldValue.Type())
+	}
+
+	if fieldValue.IsNil() {
+		fieldValue.Set(reflect.MakeMap(fieldValue.Type()))
+	}
+
+	subsections := fieldValue.Interface().(map[string]map[string]string) //nolint:forcetypeassert // type is checked above
+	if subsections[name] == nil {
+		subsections[name] = make(map[string]string)
+	}
+
+	subsections[name][key] = value
+
+	return applyWriteOperations()
+}
+
+// namedValues returns the named values of the provided name.
+func namedValues(named []sourceValue, name string) []sourceValue {
+	var values []sourceValue
+
+	for _, value := range named {
+		if value.name == name {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// joinRepeatedSliceValues joins the values of keys set multiple times, like with 'git config --add', whose field is a slice,
+// as applying them one after the other would only keep the last one.
+func joinRepeatedSliceValues[T any](values []sourceValue) []sourceValue {
+	joined := make([]sourceValue, 0, len(values))
+	indexes := make(map[string]int, len(values))
+
+	for _, value := range values {
+		path := strings.ToLower(value.path)
+
+		if idx, found := indexes[path]; found {
+			if field, _, err := reflectx.WalkToPath(new(T), value.path); err == nil && field.Kind() == reflect.Slice {
+				joined[idx].value += "," + value.value
+				continue
+			}
+		}
+
+		indexes[path] = len(joined)
+		joined = append(joined, value)
+	}
+
+	return joined
+}
+
+// splitNamedKey returns the field and the name of a key of the form '<field>.<name>.value'.
+func splitNamedKey(key string) (string, string, bool) {
+	rest, isValue := strings.CutSuffix(key, ".value")
+	if !isValue {
+		return "", "", false
+	}
+
+	field, name, found := strings.Cut(rest, ".")
+	if !found || field == "" || name == "" {
+		return "", "", false
+	}
+
+	return field, name, true
+}
+
+func newSourceOptions(opts ...SourceOption) sourceOptions {
 	o := sourceOptions{
 		gitCommandExecutor: func(ctx context.Context, args ...string) (string, error) {
 			cmd := exec.CommandContext(ctx, "git", args...)
@@ -66,49 +324,23 @@ func Source[T any](sectionName string, opts ...SourceOption) clicfg.SourceFunc[T
 		opt(&o)
 	}
 
-	return func(ctx context.Context, cfg *T) error {
-		var configs []string
-
-		{ // get git config for section
-			var gitArgs []string
-
-			if rawConfigParameters, found := os.LookupEnv(o.gitConfigParamEnvName); found {
-				configParameters, err := ParseEnvParameters(rawConfigParameters)
-				if err != nil {
-					return fmt.Errorf("could not parse git configuration parameters from env: %w", err)
-				}
-
-				for name, value := range configParameters {
-					gitArgs = append(gitArgs, "-c", name+"="+value)
-				}
-			}
-
-			gitArgs = append(gitArgs, "config", "--get-regexp", fmt.Sprintf("^%s\\..*", sectionName))
+	return o
+}
 
-			output, err := o.gitCommandExecutor(ctx, gitArgs...)
-			if err != nil {
-				return fmt.Errorf("unable to execute git config command: %w", err)
-			}
+// gitConfigArgs returns the arguments of the git config command, applying configuration parameters set in the environment.
+func (o sourceOptions) gitConfigArgs(args ...string) ([]string, error) {
+	var gitArgs []string
 
-			configs = strings.Split(strings.TrimSpace(output), "\n")
+	if rawConfigParameters, found := os.LookupEnv(o.gitConfigParamEnvName); found {
+		configParameters, err := ParseEnvParameters(rawConfigParameters)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse git configuration parameters from env: %w", err)
 		}
 
-		for _, config := range configs {
-			config = strings.TrimSpace(config)
-			if config == "" {
-				continue
-			}
-
-			parts := strings.SplitN(strings.TrimSpace(config), " ", 2)
-			if len(parts) < 2 {
-				return fmt.Errorf("could not parse git config output: %s", config)
-			}
-
-			if err := reflectx.SetToPath[T](cfg, strings.TrimPrefix(parts[0], sectionName+"."), parts[1]); err != nil && !o.ignoreSetFieldError {
-				return fmt.Errorf("unable to apply git config %q: %w", config, err)
-			}
+		for name, value := range configParameters {
+			gitArgs = append(gitArgs, "-c", name+"="+value)
 		}
-
-		return nil
 	}
+
+	return append(append(gitArgs, "config"), args...), nil
 }
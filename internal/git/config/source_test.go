@@ -3,6 +3,8 @@ package gitconfig
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
 	"testing"
 
@@ -38,18 +40,51 @@ func Test_SourceWithIgnoreConfigError(t *testing.T) {
 	test.Assert(t, o.ignoreSetFieldError)
 }
 
+func Test_SourceWithNamedSelector(t *testing.T) {
+	var o sourceOptions
+	SourceWithNamedSelector("use")(&o)
+	test.Require(t, o.namedSelector != nil)
+
+	selected, err := o.namedSelector(t.Context(), map[string][]string{"use": {"bob", "alice"}})
+	test.Assert(t, err == nil && selected == "alice", err)
+
+	selected, err = o.namedSelector(t.Context(), nil)
+	test.Assert(t, err == nil && selected == "", err)
+}
+
+func Test_SourceWithNamedSelectorFunc(t *testing.T) {
+	var o sourceOptions
+	SourceWithNamedSelectorFunc(func(context.Context, map[string][]string) (string, error) { return "bob", nil })(&o)
+	test.Require(t, o.namedSelector != nil)
+
+	selected, err := o.namedSelector(t.Context(), nil)
+	test.Assert(t, err == nil && selected == "bob", err)
+}
+
+func Test_SourceWithSubsectionFields(t *testing.T) {
+	var o sourceOptions
+	SourceWithSubsectionFields("remote", "branch")(&o)
+	test.Assert(check.Compare(t, o.subsectionFields, []string{"remote", "branch"}))
+}
+
 func Test_Source(t *testing.T) {
 	type awesomeConfig struct {
 		Name    string
 		Age     int
 		Details map[string]string
+		Use     string
+		Rules   []string
+		Branch  map[string]map[string]string
 	}
 
 	for name, tc := range map[string]struct {
 		baseConfig          awesomeConfig
+		subsectionFields    []string
 		envValue            string
 		gitConfigOutput     string
 		gitExecFailure      bool
+		namedSelector       string
+		namedSelectorFunc   func(ctx context.Context, values map[string][]string) (string, error)
 		expectedConfig      awesomeConfig
 		expectedGitArgs     []string
 		expectErrorContains string
@@ -88,6 +123,56 @@ func Test_Source(t *testing.T) {
 				"-c", "test.details.hello=world",
 			},
 		},
+		"named value selected": {
+			baseConfig:      awesomeConfig{Name: "bob"},
+			namedSelector:   "use",
+			gitConfigOutput: "test.name.alice.value alice\ntest.use alice\ntest.name.eve.value eve\ntest.name bobby\ntest.age.alice.value 30",
+			expectedConfig:  awesomeConfig{Name: "alice", Age: 30, Use: "alice"},
+		},
+		"named values ignored when not selected": {
+			baseConfig:      awesomeConfig{Name: "bob"},
+			namedSelector:   "use",
+			gitConfigOutput: "test.name.alice.value alice\ntest.age 12",
+			expectedConfig:  awesomeConfig{Name: "bob", Age: 12},
+		},
+		"repeated values of slice fields are joined": {
+			gitConfigOutput: "test.rules a=1,b=2\ntest.name bob\ntest.rules c=3\ntest.name bobby",
+			expectedConfig:  awesomeConfig{Name: "bobby", Rules: []string{"a=1", "b=2", "c=3"}},
+		},
+		"repeated values are all provided to the selector": {
+			namedSelectorFunc: func(_ context.Context, values map[string][]string) (string, error) {
+				if !slices.Equal(values["rules"], []string{"a=1,b=2", "c=3"}) {
+					return "", fmt.Errorf("unexpected values %v", values["rules"])
+				}
+
+				return "alice", nil
+			},
+			gitConfigOutput: "test.rules a=1,b=2\ntest.rules c=3\ntest.rules.alice.value d=4\ntest.rules.alice.value e=5",
+			expectedConfig:  awesomeConfig{Rules: []string{"d=4", "e=5"}},
+		},
+		"subsection names with dots": {
+			subsectionFields: []string{"branch"},
+			gitConfigOutput:  "test.branch.release-1.2.*.age 12\ntest.branch.wip/*.age 13\ntest.branch.release-1.2.*.name bob",
+			expectedConfig: awesomeConfig{Branch: map[string]map[string]string{
+				"release-1.2.*": {"age": "12", "name": "bob"},
+				"wip/*":         {"age": "13"},
+			}},
+		},
+		"subsection without key": {
+			subsectionFields:    []string{"branch"},
+			gitConfigOutput:     "test.branch.wip bob",
+			expectErrorContains: "expected a key of the form 'branch.<name>.<key>'",
+		},
+		"subsection field of another type": {
+			subsectionFields:    []string{"details"},
+			gitConfigOutput:     "test.details.a.b c",
+			expectErrorContains: "can't hold subsections",
+		},
+		"selected name without named values": {
+			namedSelector:       "use",
+			gitConfigOutput:     "test.name.alice.value alice\ntest.use eve",
+			expectErrorContains: `values named "eve" are selected but none is set`,
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			if tc.envValue != "" {
@@ -96,7 +181,7 @@ func Test_Source(t *testing.T) {
 				t.Setenv("FOO", "")
 			}
 
-			src := Source[awesomeConfig]("test",
+			opts := []SourceOption{
 				SourceWithGitConfigParameterEnvName("FOO"),
 				SourceWithGitCommandExecutor(func(_ context.Context, args ...string) (string, error) {
 					if tc.gitExecFailure {
@@ -119,7 +204,20 @@ func Test_Source(t *testing.T) {
 
 					return output.String(), nil
 				}),
-			)
+			}
+			if tc.namedSelector != "" {
+				opts = append(opts, SourceWithNamedSelector(tc.namedSelector))
+			}
+
+			if tc.subsectionFields != nil {
+				opts = append(opts, SourceWithSubsectionFields(tc.subsectionFields...))
+			}
+
+			if tc.namedSelectorFunc != nil {
+				opts = append(opts, SourceWithNamedSelectorFunc(tc.namedSelectorFunc))
+			}
+
+			src := Source[awesomeConfig]("test", opts...)
 
 			cfg := tc.baseConfig
 			err := src(t.Context(), &cfg)
@@ -133,3 +231,62 @@ func Test_Source(t *testing.T) {
 		})
 	}
 }
+
+func Test_ApplyNamed(t *testing.T) {
+	type awesomeConfig struct {
+		Name  string
+		Age   int
+		Rules []string
+	}
+
+	for name, tc := range map[string]struct {
+		gitConfigOutput     string
+		gitExecFailure      bool
+		expectedFound       bool
+		expectedConfig      awesomeConfig
+		expectErrorContains string
+	}{
+		"named values applied": {
+			gitConfigOutput: "test.name bobby\ntest.name.alice.value alice\ntest.age.eve.value 12\ntest.rules.alice.value a=1\ntest.rules.alice.value b=2",
+			expectedFound:   true,
+			expectedConfig:  awesomeConfig{Name: "alice", Age: 42, Rules: []string{"a=1", "b=2"}},
+		},
+		"no values with that name": {
+			gitConfigOutput: "test.name bobby\ntest.age.eve.value 12",
+			expectedConfig:  awesomeConfig{Name: "bob", Age: 42},
+		},
+		"unable to exec git config command": {
+			gitExecFailure:      true,
+			expectErrorContains: "unable to execute git config command",
+		},
+		"unable to apply git config": {
+			gitConfigOutput:     "test.unknown.alice.value foo",
+			expectErrorContains: "unable to apply git config",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("FOO", "")
+
+			cfg := awesomeConfig{Name: "bob", Age: 42}
+
+			found, err := ApplyNamed(t.Context(), &cfg, "test", "alice",
+				SourceWithGitConfigParameterEnvName("FOO"),
+				SourceWithGitCommandExecutor(func(context.Context, ...string) (string, error) {
+					if tc.gitExecFailure {
+						return "", errors.New("boom")
+					}
+
+					return tc.gitConfigOutput, nil
+				}),
+			)
+
+			if tc.expectErrorContains != "" {
+				test.Assert(t, err != nil && strings.Contains(err.Error(), tc.expectErrorContains), err)
+			} else {
+				test.Require(t, err == nil, err)
+				test.Assert(t, found == tc.expectedFound)
+				test.Assert(check.Compare(t, cfg, tc.expectedConfig))
+			}
+		})
+	}
+}
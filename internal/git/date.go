@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"strconv"
 	"time"
@@ -12,8 +13,9 @@ import (
 )
 
 // ResolveDate resolves a date string using git's expiry-date configuration format.
+// The value is resolved against an empty configuration file, so that it works outside of repositories too.
 func ResolveDate(ctx context.Context, value string) (time.Time, error) {
-	cmd := exec.CommandContext(ctx, "git", "config", "--local", "--type=expiry-date", "--default", shellescape.Quote(value), "--get", "wh.nonexisting")
+	cmd := exec.CommandContext(ctx, "git", "config", "--file", os.DevNull, "--type=expiry-date", "--default", shellescape.Quote(value), "--get", "wh.nonexisting")
 
 	output, err := cmd.Output()
 	if err != nil {
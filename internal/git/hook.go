@@ -0,0 +1,96 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PushedRef describes a ref update about to be pushed, as provided to the pre-push hook.
+type PushedRef struct {
+	LocalRef   string
+	LocalHash  string
+	RemoteRef  string
+	RemoteHash string
+}
+
+// IsDeletion returns whether the push deletes the remote ref.
+func (r PushedRef) IsDeletion() bool { return IsZeroHash(r.LocalHash) }
+
+// IsCreation returns whether the push creates the remote ref.
+func (r PushedRef) IsCreation() bool { return IsZeroHash(r.RemoteHash) }
+
+// ParsePrePushInput parses the '<local ref> <local sha> <remote ref> <remote sha>' lines git provides to the pre-push hook.
+func ParsePrePushInput(r io.Reader) ([]PushedRef, error) {
+	var refs []PushedRef
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unable to parse pre-push line %q: expected 4 fields, got %d", line, len(fields))
+		}
+
+		refs = append(refs, PushedRef{
+			LocalRef:   fields[0],
+			LocalHash:  fields[1],
+			RemoteRef:  fields[2],
+			RemoteHash: fields[3],
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read pre-push input: %w", err)
+	}
+
+	return refs, nil
+}
+
+// ReceivedRef describes a ref update received by the server, as provided to the pre-receive and update hooks.
+type ReceivedRef struct {
+	Ref     string
+	OldHash string
+	NewHash string
+}
+
+// IsDeletion returns whether the push deletes the ref.
+func (r ReceivedRef) IsDeletion() bool { return IsZeroHash(r.NewHash) }
+
+// IsCreation returns whether the push creates the ref.
+func (r ReceivedRef) IsCreation() bool { return IsZeroHash(r.OldHash) }
+
+// ParsePreReceiveInput parses the '<old sha> <new sha> <ref>' lines git provides to the pre-receive hook.
+func ParsePreReceiveInput(r io.Reader) ([]ReceivedRef, error) {
+	var refs []ReceivedRef
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unable to parse pre-receive line %q: expected 3 fields, got %d", line, len(fields))
+		}
+
+		refs = append(refs, ReceivedRef{
+			OldHash: fields[0],
+			NewHash: fields[1],
+			Ref:     fields[2],
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read pre-receive input: %w", err)
+	}
+
+	return refs, nil
+}
@@ -0,0 +1,87 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/krostar/test"
+	"github.com/krostar/test/check"
+)
+
+func Test_ParsePrePushInput(t *testing.T) {
+	for name, tc := range map[string]struct {
+		input              string
+		expected           []PushedRef
+		expectErrorMessage string
+	}{
+		"empty": {
+			input: "",
+		},
+		"ok": {
+			input: "refs/heads/main 1111111111111111111111111111111111111111 refs/heads/main 2222222222222222222222222222222222222222\n" +
+				"\n" +
+				"(delete) 0000000000000000000000000000000000000000 refs/heads/old 3333333333333333333333333333333333333333\n",
+			expected: []PushedRef{
+				{LocalRef: "refs/heads/main", LocalHash: "1111111111111111111111111111111111111111", RemoteRef: "refs/heads/main", RemoteHash: "2222222222222222222222222222222222222222"},
+				{LocalRef: "(delete)", LocalHash: "0000000000000000000000000000000000000000", RemoteRef: "refs/heads/old", RemoteHash: "3333333333333333333333333333333333333333"},
+			},
+		},
+		"invalid line": {
+			input:              "refs/heads/main 1111111111111111111111111111111111111111 refs/heads/main\n",
+			expectErrorMessage: "expected 4 fields, got 3",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			refs, err := ParsePrePushInput(strings.NewReader(tc.input))
+			if tc.expectErrorMessage == "" {
+				test.Require(t, err == nil, err)
+				test.Assert(check.Compare(t, refs, tc.expected))
+			} else {
+				test.Require(t, err != nil && strings.Contains(err.Error(), tc.expectErrorMessage), err)
+			}
+		})
+	}
+}
+
+func Test_PushedRef(t *testing.T) {
+	creation := PushedRef{LocalHash: "1111111111111111111111111111111111111111", RemoteHash: "0000000000000000000000000000000000000000"}
+	test.Assert(t, creation.IsCreation() && !creation.IsDeletion())
+
+	deletion := PushedRef{LocalHash: "0000000000000000000000000000000000000000", RemoteHash: "1111111111111111111111111111111111111111"}
+	test.Assert(t, !deletion.IsCreation() && deletion.IsDeletion())
+}
+
+func Test_ParsePreReceiveInput(t *testing.T) {
+	for name, tc := range map[string]struct {
+		input              string
+		expected           []ReceivedRef
+		expectErrorMessage string
+	}{
+		"empty": {
+			input: "",
+		},
+		"ok": {
+			input: "0000000000000000000000000000000000000000 1111111111111111111111111111111111111111 refs/heads/new\n" +
+				"\n" +
+				"2222222222222222222222222222222222222222 3333333333333333333333333333333333333333 refs/heads/main\n",
+			expected: []ReceivedRef{
+				{Ref: "refs/heads/new", OldHash: "0000000000000000000000000000000000000000", NewHash: "1111111111111111111111111111111111111111"},
+				{Ref: "refs/heads/main", OldHash: "2222222222222222222222222222222222222222", NewHash: "3333333333333333333333333333333333333333"},
+			},
+		},
+		"invalid line": {
+			input:              "2222222222222222222222222222222222222222 refs/heads/main\n",
+			expectErrorMessage: "expected 3 fields, got 2",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			refs, err := ParsePreReceiveInput(strings.NewReader(tc.input))
+			if tc.expectErrorMessage == "" {
+				test.Require(t, err == nil, err)
+				test.Assert(check.Compare(t, refs, tc.expected))
+			} else {
+				test.Require(t, err != nil && strings.Contains(err.Error(), tc.expectErrorMessage), err)
+			}
+		})
+	}
+}
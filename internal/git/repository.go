@@ -0,0 +1,133 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ConfigScope restricts reading and writing git configuration to a specific configuration file.
+type ConfigScope string
+
+// Configuration scopes.
+const (
+	ConfigScopeAll    ConfigScope = ""
+	ConfigScopeLocal  ConfigScope = "--local"
+	ConfigScopeGlobal ConfigScope = "--global"
+)
+
+// CommonDir returns the absolute path of the repository's git directory, shared between worktrees.
+func CommonDir(ctx context.Context) (string, error) {
+	output, err := execGit(ctx, "", "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return "", fmt.Errorf("unable to find git directory: %w", err)
+	}
+
+	return strings.TrimSpace(output), nil
+}
+
+// RepositoryPath returns the absolute path of the repository's working tree, or of its git directory for bare repositories.
+func RepositoryPath(ctx context.Context) (string, error) {
+	output, err := execGit(ctx, "", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return CommonDir(ctx)
+	}
+
+	return strings.TrimSpace(output), nil
+}
+
+// HooksDir returns the absolute path of the directory git runs hooks from, which is set by core.hooksPath if any.
+func HooksDir(ctx context.Context) (string, error) {
+	output, err := execGit(ctx, "", "rev-parse", "--path-format=absolute", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("unable to find hooks directory: %w", err)
+	}
+
+	return strings.TrimSpace(output), nil
+}
+
+// CurrentBranch returns the short name of the checked out branch, or an empty string if HEAD is detached.
+func CurrentBranch(ctx context.Context) (string, error) {
+	output, err := execGit(ctx, "", "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		if exitErr := (*exec.ExitError)(nil); errors.As(err, &exitErr) && exitErr.ExitCode() == 1 { // detached HEAD
+			return "", nil
+		}
+
+		return "", fmt.Errorf("unable to get current branch: %w", err)
+	}
+
+	return strings.TrimSpace(output), nil
+}
+
+// AuthorEmail returns the email git uses as commit author, from GIT_AUTHOR_EMAIL, author.email, or user.email.
+func AuthorEmail(ctx context.Context) (string, error) {
+	output, err := execGit(ctx, "", "var", "GIT_AUTHOR_IDENT")
+	if err != nil {
+		return "", fmt.Errorf("unable to get author identity: %w", err)
+	}
+
+	_, rest, found := strings.Cut(output, "<")
+	if !found {
+		return "", fmt.Errorf("unable to parse author identity %q", strings.TrimSpace(output))
+	}
+
+	email, _, found := strings.Cut(rest, ">")
+	if !found {
+		return "", fmt.Errorf("unable to parse author identity %q", strings.TrimSpace(output))
+	}
+
+	return email, nil
+}
+
+// GetConfig returns the value of the configuration key in the provided scope, or an empty string if it is not set.
+func GetConfig(ctx context.Context, scope ConfigScope, key string) (string, error) {
+	return getConfig(ctx, scope, key)
+}
+
+// GetConfigPath returns the value of the configuration key in the provided scope, expanded as a path,
+// or an empty string if it is not set.
+func GetConfigPath(ctx context.Context, scope ConfigScope, key string) (string, error) {
+	return getConfig(ctx, scope, key, "--type=path")
+}
+
+func getConfig(ctx context.Context, scope ConfigScope, key string, args ...string) (string, error) {
+	output, err := execGit(ctx, "", configArgs(scope, append(args, "--get", key)...)...)
+	if err != nil {
+		if exitErr := (*exec.ExitError)(nil); errors.As(err, &exitErr) && exitErr.ExitCode() == 1 { // config not found
+			return "", nil
+		}
+
+		return "", fmt.Errorf("unable to get config %s: %w", key, err)
+	}
+
+	return strings.TrimSpace(output), nil
+}
+
+// SetConfig sets the configuration key in the provided scope.
+func SetConfig(ctx context.Context, scope ConfigScope, key, value string) error {
+	if _, err := execGit(ctx, "", configArgs(scope, key, value)...); err != nil {
+		return fmt.Errorf("unable to set config %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// UnsetConfig removes the configuration key from the provided scope.
+func UnsetConfig(ctx context.Context, scope ConfigScope, key string) error {
+	if _, err := execGit(ctx, "", configArgs(scope, "--unset", key)...); err != nil {
+		return fmt.Errorf("unable to unset config %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func configArgs(scope ConfigScope, args ...string) []string {
+	if scope == ConfigScopeAll {
+		return append([]string{"config"}, args...)
+	}
+
+	return append([]string{"config", string(scope)}, args...)
+}
@@ -0,0 +1,182 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CommitDates holds the author and committer dates of a commit.
+type CommitDates struct {
+	Author    time.Time
+	Committer time.Time
+}
+
+// RewriteCommitDates recreates the provided commits with new author and committer dates, and returns
+// the hashes of the recreated commits indexed by their original hash.
+// Commits must be sorted parents first, commits having a recreated parent are recreated as well to keep the history consistent.
+// Signatures of recreated commits are dropped as they would no longer be valid.
+func RewriteCommitDates(ctx context.Context, commits []Commit, dates map[string]CommitDates) (map[string]string, error) {
+	rewritten := make(map[string]string)
+
+	for _, commit := range commits {
+		newDates, hasNewDates := dates[commit.Hash]
+
+		var hasNewParent bool
+		for _, parent := range commit.Parents {
+			if _, found := rewritten[parent]; found {
+				hasNewParent = true
+			}
+		}
+
+		if !hasNewDates && !hasNewParent {
+			continue
+		}
+
+		raw, err := execGit(ctx, "", "cat-file", "commit", commit.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read commit %s: %w", commit.Hash, err)
+		}
+
+		var newDatesPtr *CommitDates
+		if hasNewDates {
+			newDatesPtr = &newDates
+		}
+
+		newHash, err := execGit(ctx, rewriteCommitObject(raw, rewritten, newDatesPtr), "hash-object", "-t", "commit", "-w", "--stdin")
+		if err != nil {
+			return nil, fmt.Errorf("unable to write rewritten commit %s: %w", commit.Hash, err)
+		}
+
+		rewritten[commit.Hash] = strings.TrimSpace(newHash)
+	}
+
+	return rewritten, nil
+}
+
+// rewriteCommitObject rewrites the raw commit object headers, replacing parents with their rewritten version,
+// dates with the provided ones if any, and dropping signatures.
+func rewriteCommitObject(raw string, rewrittenParents map[string]string, dates *CommitDates) string {
+	headers, message, _ := strings.Cut(raw, "\n\n")
+	headers = strings.TrimSuffix(headers, "\n")
+
+	var (
+		rewritten   strings.Builder
+		inSignature bool
+	)
+
+	for line := range strings.SplitSeq(headers, "\n") {
+		if inSignature && strings.HasPrefix(line, " ") {
+			continue
+		}
+
+		inSignature = false
+
+		key, value, _ := strings.Cut(line, " ")
+
+		switch key {
+		case "parent":
+			if newParent, found := rewrittenParents[value]; found {
+				value = newParent
+			}
+		case "author":
+			if dates != nil {
+				value = replaceIdentityDate(value, dates.Author)
+			}
+		case "committer":
+			if dates != nil {
+				value = replaceIdentityDate(value, dates.Committer)
+			}
+		case "gpgsig", "gpgsig-sha256":
+			inSignature = true
+			continue
+		}
+
+		rewritten.WriteString(key + " " + value + "\n")
+	}
+
+	return rewritten.String() + "\n" + message
+}
+
+// replaceIdentityDate replaces the date of an identity formatted like 'Name <email> 1700000000 +0200'.
+func replaceIdentityDate(identity string, date time.Time) string {
+	endOfEmail := strings.LastIndex(identity, ">")
+	if endOfEmail < 0 {
+		return identity
+	}
+
+	return identity[:endOfEmail+1] + " " + strconv.FormatInt(date.Unix(), 10) + " " + date.Format("-0700")
+}
+
+// UpdateRef updates the ref to the new hash, only if it still points to the old hash.
+func UpdateRef(ctx context.Context, ref, newHash, oldHash, reason string) error {
+	if _, err := execGit(ctx, "", "update-ref", "-m", reason, ref, newHash, oldHash); err != nil {
+		return fmt.Errorf("unable to update ref %s: %w", ref, err)
+	}
+
+	return nil
+}
+
+// ResolveRef returns the hash of the commit the ref points to, and whether the ref exists.
+func ResolveRef(ctx context.Context, ref string) (string, bool) {
+	output, err := execGit(ctx, "", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", false
+	}
+
+	return strings.TrimSpace(output), true
+}
+
+// RevisionRangeRefs returns the full names of the refs whose commits are included by the revision range,
+// like 'refs/heads/main' for 'origin/main..main' or 'origin/main..HEAD' when HEAD points to main.
+// A detached HEAD is returned as 'HEAD', revisions that aren't refs, like hashes, are ignored.
+func RevisionRangeRefs(ctx context.Context, revisionRange string) ([]string, error) {
+	output, err := execGit(ctx, "", "rev-parse", "--symbolic-full-name", revisionRange, "--")
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve revision range %s: %w", revisionRange, err)
+	}
+
+	var refs []string
+
+	for line := range strings.SplitSeq(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" && line != "--" && !strings.HasPrefix(line, "^") {
+			refs = append(refs, line)
+		}
+	}
+
+	return refs, nil
+}
+
+// CommitExists returns whether the provided hash exists in the repository as a commit.
+func CommitExists(ctx context.Context, hash string) bool {
+	_, err := execGit(ctx, "", "cat-file", "-e", hash+"^{commit}")
+	return err == nil
+}
+
+// IsZeroHash returns whether the provided hash is git's null object name, used to describe non-existing refs.
+func IsZeroHash(hash string) bool {
+	return hash != "" && strings.Trim(hash, "0") == ""
+}
+
+func execGit(ctx context.Context, stdin string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		var stdErr string
+		if exitErr := (*exec.ExitError)(nil); errors.As(err, &exitErr) {
+			stdErr = "; stderr: " + string(exitErr.Stderr)
+		}
+
+		return "", fmt.Errorf("unable to execute git command %q: %w%s", strings.Join(append([]string{"git"}, args...), " "), err, stdErr)
+	}
+
+	return string(output), nil
+}
@@ -0,0 +1,61 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/krostar/test"
+)
+
+func Test_rewriteCommitObject(t *testing.T) {
+	raw := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+		"parent aaaa\n" +
+		"parent bbbb\n" +
+		"author Bob <bob@example.com> 1585346423 +0200\n" +
+		"committer Alice <alice@example.com> 1585346500 +0200\n" +
+		"gpgsig -----BEGIN PGP SIGNATURE-----\n" +
+		" \n" +
+		" abcdef\n" +
+		" -----END PGP SIGNATURE-----\n" +
+		"mergetag object cccc\n" +
+		" type commit\n" +
+		"\n" +
+		"subject\n\nbody\n"
+
+	t.Run("with dates", func(t *testing.T) {
+		date := time.Date(2020, time.March, 27, 17, 30, 0, 0, time.FixedZone("", -5*60*60))
+
+		test.Assert(t, rewriteCommitObject(raw, map[string]string{"bbbb": "dddd"}, &CommitDates{Author: date, Committer: date.Add(time.Minute)}) == ""+
+			"tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n"+
+			"parent aaaa\n"+
+			"parent dddd\n"+
+			"author Bob <bob@example.com> 1585348200 -0500\n"+
+			"committer Alice <alice@example.com> 1585348260 -0500\n"+
+			"mergetag object cccc\n"+
+			" type commit\n"+
+			"\n"+
+			"subject\n\nbody\n",
+		)
+	})
+
+	t.Run("without dates", func(t *testing.T) {
+		test.Assert(t, rewriteCommitObject(raw, nil, nil) == ""+
+			"tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n"+
+			"parent aaaa\n"+
+			"parent bbbb\n"+
+			"author Bob <bob@example.com> 1585346423 +0200\n"+
+			"committer Alice <alice@example.com> 1585346500 +0200\n"+
+			"mergetag object cccc\n"+
+			" type commit\n"+
+			"\n"+
+			"subject\n\nbody\n",
+		)
+	})
+}
+
+func Test_IsZeroHash(t *testing.T) {
+	test.Assert(t, IsZeroHash("0000000000000000000000000000000000000000"))
+	test.Assert(t, IsZeroHash("0000000000000000000000000000000000000000000000000000000000000000"))
+	test.Assert(t, !IsZeroHash("0000000000000000000000000000000000000001"))
+	test.Assert(t, !IsZeroHash(""))
+}
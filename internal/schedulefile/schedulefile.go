@@ -0,0 +1,498 @@
+package schedulefile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/krostar/git-workhours/internal/workhours"
+)
+
+// Definition describes a schedule, either at the top of the file or in a profile.
+type Definition struct {
+	// Timezone is the IANA timezone in which the schedule is evaluated.
+	Timezone string `json:"timezone" toml:"timezone" yaml:"timezone"`
+	// Schedule is the weekly schedule, in any format workhours.ParseWeeklySchedule accepts.
+	Schedule string `json:"schedule" toml:"schedule" yaml:"schedule"`
+	// Weekly is the weekly schedule as shifts like '09:00-12:00' by day name, it can't be set along with Schedule.
+	Weekly map[string][]string `json:"weekly" toml:"weekly" yaml:"weekly"`
+	// Holidays are dates or date ranges during which no work is scheduled.
+	Holidays []string `json:"holidays" toml:"holidays" yaml:"holidays"`
+	// Exceptions are dates or date ranges overriding the weekly schedule, like '2026-12-24=9h-12h'.
+	Exceptions []string `json:"exceptions" toml:"exceptions" yaml:"exceptions"`
+	// TimeOff are dates or date ranges during which no work is expected at all.
+	TimeOff []string `json:"timeoff" toml:"timeoff" yaml:"timeoff"`
+}
+
+// File is a schedule definition loaded from a file, with optional named profiles.
+type File struct {
+	Definition `yaml:",inline"`
+
+	Profiles map[string]Definition `json:"profiles" toml:"profiles" yaml:"profiles"`
+}
+
+// Error describes an invalid schedule file, located by its line when it could be found.
+type Error struct {
+	Path string
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Msg)
+	}
+
+	return e.Path + ": " + e.Msg
+}
+
+// Load reads and validates the schedule file, whose format is deduced from its extension: .toml, .yaml, .yml, or .json.
+// Paths starting with '~/' are relative to the home directory.
+func Load(path string) (File, error) {
+	if rest, found := strings.CutPrefix(path, "~/"); found {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return File{}, fmt.Errorf("unable to get home directory: %w", err)
+		}
+
+		path = filepath.Join(home, rest)
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // path is provided by the user
+	if err != nil {
+		return File{}, fmt.Errorf("unable to read schedule file: %w", err)
+	}
+
+	f := file{path: path, data: data}
+
+	var loaded File
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".toml":
+		err = f.decodeTOML(&loaded)
+	case ".yaml", ".yml":
+		err = f.decodeYAML(&loaded)
+	case ".json":
+		err = f.decodeJSON(&loaded)
+	default:
+		return File{}, &Error{Path: path, Msg: fmt.Sprintf("unsupported schedule file format %q, expected .toml, .yaml, .yml, or .json", ext)}
+	}
+
+	if err != nil {
+		return File{}, err
+	}
+
+	if err := f.validate(loaded.Definition, nil); err != nil {
+		return File{}, err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(loaded.Profiles)) {
+		if err := f.validate(loaded.Profiles[name], []string{"profiles", name}); err != nil {
+			return File{}, err
+		}
+	}
+
+	return loaded, nil
+}
+
+// Profile returns the definition of the named profile, whose unset fields are inherited from the top of the file,
+// holidays, exceptions, and time off being added to the ones at the top of the file. An empty name returns the top of the file.
+func (f File) Profile(name string) (Definition, error) {
+	if name == "" {
+		return f.Definition, nil
+	}
+
+	profile, found := f.Profiles[name]
+	if !found {
+		return Definition{}, fmt.Errorf("schedule profile %q is not defined", name)
+	}
+
+	def := f.Definition
+
+	if profile.Timezone != "" {
+		def.Timezone = profile.Timezone
+	}
+
+	if profile.Schedule != "" || len(profile.Weekly) > 0 {
+		def.Schedule, def.Weekly = profile.Schedule, profile.Weekly
+	}
+
+	def.Holidays = append(slices.Clone(def.Holidays), profile.Holidays...)
+	def.Exceptions = append(slices.Clone(def.Exceptions), profile.Exceptions...)
+	def.TimeOff = append(slices.Clone(def.TimeOff), profile.TimeOff...)
+
+	return def, nil
+}
+
+// WeeklySchedule returns the weekly schedule in a format workhours.ParseWeeklySchedule accepts, or an empty string if none is defined.
+func (d Definition) WeeklySchedule() string {
+	if d.Schedule != "" || len(d.Weekly) == 0 {
+		return d.Schedule
+	}
+
+	var entries []string
+
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		for day, shifts := range d.Weekly {
+			if weekday, ok := parseWeekday(day); ok && weekday == wd && len(shifts) > 0 {
+				entries = append(entries, strings.ToLower(wd.String()[:3])+" "+strings.Join(shifts, ","))
+			}
+		}
+	}
+
+	return strings.Join(entries, "; ")
+}
+
+type file struct {
+	path string
+	data []byte
+	// lines holds the line of each key and list item of the file, by path, see keyPath.
+	lines map[string]int
+}
+
+// validate checks every value of the definition, found at the provided path of the file.
+func (f file) validate(def Definition, at []string) error {
+	path := func(keys ...string) []string { return append(slices.Clone(at), keys...) }
+
+	if def.Timezone != "" {
+		if _, err := time.LoadLocation(def.Timezone); err != nil {
+			return f.errorAt(path("timezone"), "invalid timezone %q: %v", def.Timezone, err)
+		}
+	}
+
+	if def.Schedule != "" && len(def.Weekly) > 0 {
+		return f.errorAt(path("weekly"), "schedule and weekly can't be both set")
+	}
+
+	if def.Schedule != "" {
+		if _, err := workhours.ParseWeeklySchedule(def.Schedule); err != nil {
+			return f.errorAt(path("schedule"), "invalid schedule: %v", err)
+		}
+	}
+
+	days := make(map[time.Weekday]string, len(def.Weekly))
+
+	for _, day := range slices.Sorted(maps.Keys(def.Weekly)) {
+		weekday, ok := parseWeekday(day)
+		if !ok {
+			return f.errorAt(path("weekly", day), "unknown day %q, expected one of sun, mon, tue, wed, thu, fri, sat", day)
+		}
+
+		if other, scheduled := days[weekday]; scheduled {
+			first, second := other, day
+			if f.lineAt(path("weekly", first)) > f.lineAt(path("weekly", second)) {
+				first, second = second, first
+			}
+
+			return f.errorAt(path("weekly", second), "day %q is already scheduled by %q", second, first)
+		}
+
+		days[weekday] = day
+
+		for i, shift := range def.Weekly[day] {
+			prefix := strings.ToLower(weekday.String()) + " "
+
+			if _, err := workhours.ParseWeeklySchedule(prefix + shift); err != nil {
+				if syntaxErr := (*workhours.SyntaxError)(nil); errors.As(err, &syntaxErr) { // locate the mistake in the shift itself
+					err = &workhours.SyntaxError{Column: syntaxErr.Column - len(prefix), Msg: syntaxErr.Msg}
+				}
+
+				return f.errorAt(path("weekly", day, strconv.Itoa(i)), "invalid %s shift %q: %v", weekday, shift, err)
+			}
+		}
+	}
+
+	if raw := def.WeeklySchedule(); len(def.Weekly) > 0 && raw != "" {
+		if _, err := workhours.ParseWeeklySchedule(raw); err != nil {
+			return f.errorAt(path("weekly"), "invalid weekly schedule: %v", err)
+		}
+	}
+
+	for i, raw := range def.Holidays {
+		if _, err := workhours.ParseDateRange(raw); err != nil {
+			return f.errorAt(path("holidays", strconv.Itoa(i)), "invalid holiday: %v", err)
+		}
+	}
+
+	for i, raw := range def.Exceptions {
+		if _, err := workhours.ParseScheduleException(raw); err != nil {
+			return f.errorAt(path("exceptions", strconv.Itoa(i)), "invalid exception: %v", err)
+		}
+	}
+
+	for i, raw := range def.TimeOff {
+		if _, err := workhours.ParseDateRange(raw); err != nil {
+			return f.errorAt(path("timeoff", strconv.Itoa(i)), "invalid time off: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (f *file) decodeTOML(dst *File) error {
+	err := toml.NewDecoder(bytes.NewReader(f.data)).DisallowUnknownFields().Decode(dst)
+
+	if strictErr := (*toml.StrictMissingError)(nil); errors.As(err, &strictErr) && len(strictErr.Errors) > 0 {
+		line, _ := strictErr.Errors[0].Position()
+		return &Error{Path: f.path, Line: line, Msg: "unknown field " + strings.Join(strictErr.Errors[0].Key(), ".")}
+	}
+
+	if decodeErr := (*toml.DecodeError)(nil); errors.As(err, &decodeErr) {
+		line, _ := decodeErr.Position()
+		return &Error{Path: f.path, Line: line, Msg: decodeErr.Error()}
+	}
+
+	if err != nil {
+		return &Error{Path: f.path, Msg: err.Error()}
+	}
+
+	f.lines = make(map[string]int)
+
+	var parser unstable.Parser
+	parser.Reset(f.data)
+
+	var table []string
+
+	for parser.NextExpression() {
+		expr := parser.Expression()
+
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			table = f.addTOMLKey(nil, expr.Key())
+		case unstable.KeyValue:
+			f.addTOMLValue(f.addTOMLKey(table, expr.Key()), expr.Value())
+		}
+	}
+
+	return nil
+}
+
+// addTOMLKey records the lines of the dotted key, found under the provided path, and returns the path of the key.
+func (f file) addTOMLKey(path []string, key unstable.Iterator) []string {
+	path = slices.Clone(path)
+
+	for key.Next() {
+		path = append(path, string(key.Node().Data))
+		f.addLine(path, f.lineOfOffset(int64(key.Node().Raw.Offset)))
+	}
+
+	return path
+}
+
+// addTOMLValue records the lines of the items of the value, and of the keys of inline tables, found at the provided path.
+func (f file) addTOMLValue(path []string, value *unstable.Node) {
+	children := value.Children()
+
+	for i := 0; children.Next(); i++ {
+		child := children.Node()
+
+		switch value.Kind {
+		case unstable.Array:
+			itemPath := append(slices.Clone(path), strconv.Itoa(i))
+			if child.Raw.Length > 0 {
+				f.addLine(itemPath, f.lineOfOffset(int64(child.Raw.Offset)))
+			}
+
+			f.addTOMLValue(itemPath, child)
+		case unstable.InlineTable:
+			f.addTOMLValue(f.addTOMLKey(path, child.Key()), child.Value())
+		}
+	}
+}
+
+// yamlErrorLine matches the line yaml errors start with.
+var yamlErrorLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+func (f *file) decodeYAML(dst *File) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(f.data))
+	decoder.KnownFields(true)
+
+	err := decoder.Decode(dst)
+	if err == nil || errors.Is(err, io.EOF) {
+		var root yaml.Node
+		if err := yaml.Unmarshal(f.data, &root); err != nil {
+			return &Error{Path: f.path, Msg: err.Error()}
+		}
+
+		f.lines = make(map[string]int)
+		if len(root.Content) > 0 {
+			f.addYAMLNode(nil, root.Content[0])
+		}
+
+		return nil
+	}
+
+	msg := err.Error()
+	if typeErr := (*yaml.TypeError)(nil); errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		msg = typeErr.Errors[0]
+	}
+
+	if match := yamlErrorLine.FindStringSubmatch(msg); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return &Error{Path: f.path, Line: line, Msg: match[2]}
+	}
+
+	return &Error{Path: f.path, Msg: msg}
+}
+
+// addYAMLNode records the lines of the keys and items of the node, found at the provided path.
+func (f file) addYAMLNode(path []string, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyPath := append(slices.Clone(path), node.Content[i].Value)
+			f.addLine(keyPath, node.Content[i].Line)
+			f.addYAMLNode(keyPath, node.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := append(slices.Clone(path), strconv.Itoa(i))
+			f.addLine(itemPath, item.Line)
+			f.addYAMLNode(itemPath, item)
+		}
+	}
+}
+
+// jsonUnknownField matches the error returned when decoding an unknown field.
+var jsonUnknownField = regexp.MustCompile(`^json: unknown field "(.*)"$`)
+
+func (f *file) decodeJSON(dst *File) error {
+	decoder := json.NewDecoder(bytes.NewReader(f.data))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(dst)
+
+	if err == nil || errors.Is(err, io.EOF) || jsonUnknownField.MatchString(err.Error()) {
+		f.lines = make(map[string]int)
+		_ = f.addJSONValue(json.NewDecoder(bytes.NewReader(f.data)), nil) // the document is valid, and fully read by the decoder
+	}
+
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	if syntaxErr := (*json.SyntaxError)(nil); errors.As(err, &syntaxErr) {
+		return &Error{Path: f.path, Line: f.lineOfOffset(syntaxErr.Offset), Msg: syntaxErr.Error()}
+	}
+
+	if typeErr := (*json.UnmarshalTypeError)(nil); errors.As(err, &typeErr) {
+		return &Error{Path: f.path, Line: f.lineOfOffset(typeErr.Offset), Msg: fmt.Sprintf("invalid value for %s, expected %s", typeErr.Field, typeErr.Type)}
+	}
+
+	if match := jsonUnknownField.FindStringSubmatch(err.Error()); match != nil {
+		return &Error{Path: f.path, Line: f.lineOfKey(match[1]), Msg: "unknown field " + match[1]}
+	}
+
+	return &Error{Path: f.path, Msg: err.Error()}
+}
+
+// addJSONValue reads the next value of the decoder, and records the lines of its keys and items, found at the provided path.
+func (f file) addJSONValue(decoder *json.Decoder, path []string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+
+			keyPath := append(slices.Clone(path), fmt.Sprint(key))
+			f.addLine(keyPath, f.lineOfOffset(decoder.InputOffset()))
+
+			if err := f.addJSONValue(decoder, keyPath); err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for i := 0; decoder.More(); i++ {
+			itemPath := append(slices.Clone(path), strconv.Itoa(i))
+			if err := f.addJSONValue(decoder, itemPath); err != nil {
+				return err
+			}
+		}
+	default:
+		f.addLine(path, f.lineOfOffset(decoder.InputOffset()))
+		return nil
+	}
+
+	_, err = decoder.Token() // closing delimiter
+
+	return err
+}
+
+// errorAt returns an error located at the line of the provided path.
+func (f file) errorAt(path []string, format string, args ...any) error {
+	return &Error{Path: f.path, Line: f.lineAt(path), Msg: fmt.Sprintf(format, args...)}
+}
+
+// keyPath returns the key of the lines of the provided path, made of keys and list indexes.
+func keyPath(path []string) string {
+	return strings.Join(path, "\x00")
+}
+
+func (f file) addLine(path []string, line int) {
+	if _, found := f.lines[keyPath(path)]; !found {
+		f.lines[keyPath(path)] = line
+	}
+}
+
+// lineAt returns the line of the provided path, or of its closest parent when it can't be found, or 0.
+func (f file) lineAt(path []string) int {
+	for n := len(path); n > 0; n-- {
+		if line, found := f.lines[keyPath(path[:n])]; found {
+			return line
+		}
+	}
+
+	return 0
+}
+
+// lineOfKey returns the first line setting the key, wherever it is in the file, or 0 if not found.
+func (f file) lineOfKey(key string) int {
+	var first int
+
+	for path, line := range f.lines {
+		if keys := strings.Split(path, "\x00"); keys[len(keys)-1] == key && (first == 0 || line < first) {
+			first = line
+		}
+	}
+
+	return first
+}
+
+func (f file) lineOfOffset(offset int64) int {
+	offset = min(offset, int64(len(f.data)))
+	return bytes.Count(f.data[:offset], []byte("\n")) + 1
+}
+
+// parseWeekday parses a day name, either abbreviated like 'mon', or in full like 'monday'.
+func parseWeekday(raw string) (time.Weekday, bool) {
+	name := strings.ToLower(strings.TrimSpace(raw))
+
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		if full := strings.ToLower(wd.String()); name == full || name == full[:3] {
+			return wd, true
+		}
+	}
+
+	return 0, false
+}
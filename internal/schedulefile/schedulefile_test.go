@@ -0,0 +1,269 @@
+package schedulefile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/krostar/test"
+	"github.com/krostar/test/check"
+)
+
+func Test_Load(t *testing.T) {
+	expected := File{
+		Definition: Definition{
+			Timezone: "Europe/Paris",
+			Weekly:   map[string][]string{"mon": {"09:00-12:00", "13:00-18:00"}, "friday": {"09:00-12:00"}},
+			Holidays: []string{"2026-12-25"},
+		},
+		Profiles: map[string]Definition{
+			"client-a": {Schedule: "tue-thu 14:00-18:00", Exceptions: []string{"2026-12-24=9h-12h"}},
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		filename            string
+		content             string
+		expected            File
+		expectErrorLocation string
+		expectErrorContains string
+	}{
+		"toml": {
+			filename: "schedule.toml",
+			content: `timezone = "Europe/Paris"
+holidays = ["2026-12-25"]
+
+[weekly]
+mon = ["09:00-12:00", "13:00-18:00"]
+friday = ["09:00-12:00"]
+
+[profiles.client-a]
+schedule = "tue-thu 14:00-18:00"
+exceptions = ["2026-12-24=9h-12h"]
+`,
+			expected: expected,
+		},
+		"yaml": {
+			filename: "schedule.yml",
+			content: `timezone: Europe/Paris
+holidays: ["2026-12-25"]
+weekly:
+  mon: ["09:00-12:00", "13:00-18:00"]
+  friday: ["09:00-12:00"]
+profiles:
+  client-a:
+    schedule: tue-thu 14:00-18:00
+    exceptions: ["2026-12-24=9h-12h"]
+`,
+			expected: expected,
+		},
+		"json": {
+			filename: "schedule.json",
+			content: `{
+  "timezone": "Europe/Paris",
+  "holidays": ["2026-12-25"],
+  "weekly": {"mon": ["09:00-12:00", "13:00-18:00"], "friday": ["09:00-12:00"]},
+  "profiles": {
+    "client-a": {"schedule": "tue-thu 14:00-18:00", "exceptions": ["2026-12-24=9h-12h"]}
+  }
+}`,
+			expected: expected,
+		},
+		"empty yaml": {
+			filename: "schedule.yaml",
+		},
+		"unsupported format": {
+			filename:            "schedule.ini",
+			expectErrorContains: `unsupported schedule file format ".ini"`,
+		},
+		"toml syntax error": {
+			filename:            "schedule.toml",
+			content:             "timezone = \"UTC\"\nschedule = \n",
+			expectErrorLocation: "schedule.toml:2",
+		},
+		"toml unknown field": {
+			filename:            "schedule.toml",
+			content:             "timezone = \"UTC\"\nshedule = \"mon 09:00-18:00\"\n",
+			expectErrorLocation: "schedule.toml:2",
+			expectErrorContains: "unknown field shedule",
+		},
+		"yaml syntax error": {
+			filename:            "schedule.yaml",
+			content:             "timezone: UTC\nweekly: [\n",
+			expectErrorLocation: "schedule.yaml:2",
+		},
+		"yaml unknown field": {
+			filename:            "schedule.yaml",
+			content:             "timezone: UTC\nshedule: mon 09:00-18:00\n",
+			expectErrorLocation: "schedule.yaml:2",
+			expectErrorContains: "field shedule not found",
+		},
+		"json syntax error": {
+			filename:            "schedule.json",
+			content:             "{\n  \"timezone\": \"UTC\"\n  \"schedule\": \"mon 09:00-18:00\"\n}",
+			expectErrorLocation: "schedule.json:3",
+		},
+		"json invalid type": {
+			filename:            "schedule.json",
+			content:             "{\n  \"timezone\": \"UTC\",\n  \"holidays\": \"2026-12-25\"\n}",
+			expectErrorLocation: "schedule.json:3",
+			expectErrorContains: "invalid value for holidays",
+		},
+		"json unknown field": {
+			filename:            "schedule.json",
+			content:             "{\n  \"timezone\": \"UTC\",\n  \"shedule\": \"mon 09:00-18:00\"\n}",
+			expectErrorLocation: "schedule.json:3",
+			expectErrorContains: "unknown field shedule",
+		},
+		"invalid timezone": {
+			filename:            "schedule.toml",
+			content:             "schedule = \"mon 09:00-18:00\"\ntimezone = \"Europe/Nowhere\"\n",
+			expectErrorLocation: "schedule.toml:2",
+			expectErrorContains: `invalid timezone "Europe/Nowhere"`,
+		},
+		"invalid schedule": {
+			filename:            "schedule.toml",
+			content:             "timezone = \"UTC\"\nschedule = \"mon-fry 09:00-18:00\"\n",
+			expectErrorLocation: "schedule.toml:2",
+			expectErrorContains: `invalid schedule: column 5: unknown day "fry"`,
+		},
+		"schedule and weekly": {
+			filename:            "schedule.yaml",
+			content:             "schedule: mon 09:00-18:00\nweekly:\n  tue: [\"09:00-18:00\"]\n",
+			expectErrorLocation: "schedule.yaml:2",
+			expectErrorContains: "schedule and weekly can't be both set",
+		},
+		"invalid weekly day": {
+			filename:            "schedule.yaml",
+			content:             "weekly:\n  mon: [\"09:00-18:00\"]\n  fry: [\"09:00-18:00\"]\n",
+			expectErrorLocation: "schedule.yaml:3",
+			expectErrorContains: `unknown day "fry"`,
+		},
+		"weekly day set twice": {
+			filename:            "schedule.yaml",
+			content:             "weekly:\n  monday: [\"09:00-18:00\"]\n  tue: [\"09:00-18:00\"]\n  mon: [\"10:00-12:00\"]\n",
+			expectErrorLocation: "schedule.yaml:4",
+			expectErrorContains: `day "mon" is already scheduled by "monday"`,
+		},
+		"weekly day set twice in profile": {
+			filename:            "schedule.toml",
+			content:             "[profiles.a.weekly]\nMon = [\"09:00-18:00\"]\n\n[profiles.b.weekly]\nmon = [\"09:00-18:00\"]\nMonday = [\"10:00-12:00\"]\n",
+			expectErrorLocation: "schedule.toml:6",
+			expectErrorContains: `day "Monday" is already scheduled by "mon"`,
+		},
+		"weekly day set twice in json": {
+			filename:            "schedule.json",
+			content:             "{\"weekly\": {\n  \"mon\": [\"09:00-18:00\"],\n  \"Mon\": [\"10:00-12:00\"]\n}}",
+			expectErrorLocation: "schedule.json:3",
+			expectErrorContains: `day "Mon" is already scheduled by "mon"`,
+		},
+		"invalid weekly shift": {
+			filename:            "schedule.yaml",
+			content:             "weekly:\n  mon: [\"09:00-18:00\"]\n  tue: [\"09:00-18:5\"]\n",
+			expectErrorLocation: "schedule.yaml:3",
+			expectErrorContains: `invalid Tuesday shift "09:00-18:5": column 10: expected minutes on two digits`,
+		},
+		"weekly overlap": {
+			filename:            "schedule.yaml",
+			content:             "timezone: UTC\nweekly:\n  mon: [\"22:00-06:00\"]\n  tue: [\"05:00-12:00\"]\n",
+			expectErrorLocation: "schedule.yaml:2",
+			expectErrorContains: "Monday's last shift overlaps with Tuesday's first shift",
+		},
+		"invalid holiday in profile": {
+			filename:            "schedule.toml",
+			content:             "holidays = [\"2026-12-25\"]\n\n[profiles.a]\nholidays = [\"2026-12-25\"]\n\n[profiles.b]\nholidays = [\"2026-12-25..2026-12-20\"]\n",
+			expectErrorLocation: "schedule.toml:7",
+			expectErrorContains: "invalid holiday",
+		},
+		"invalid value found earlier in the file": {
+			filename:            "schedule.toml",
+			content:             "schedule = \"mon 09:00-18:00\" # was mon 09:00-18:00x\n\n[profiles.a]\nschedule = \"mon 09:00-18:00x\"\n",
+			expectErrorLocation: "schedule.toml:4",
+			expectErrorContains: "invalid schedule",
+		},
+		"invalid item of a profile named like a key": {
+			filename:            "schedule.yaml",
+			content:             "timezone: UTC\nprofiles:\n  timezone:\n    timezone: UTC\n    holidays:\n      - 2026-12-25\n      - 2026-13-01\n",
+			expectErrorLocation: "schedule.yaml:7",
+			expectErrorContains: "invalid holiday",
+		},
+		"invalid item in json": {
+			filename:            "schedule.json",
+			content:             "{\n  \"timeoff\": [\n    \"2026-12-25\",\n    \"2026-13-01\"\n  ]\n}",
+			expectErrorLocation: "schedule.json:4",
+			expectErrorContains: "invalid time off",
+		},
+		"invalid exception": {
+			filename:            "schedule.toml",
+			content:             "exceptions = [\"2026-12-24=9h\"]\n",
+			expectErrorLocation: "schedule.toml:1",
+			expectErrorContains: "invalid exception",
+		},
+		"invalid time off": {
+			filename:            "schedule.toml",
+			content:             "timeoff = [\"2026-13-01\"]\n",
+			expectErrorLocation: "schedule.toml:1",
+			expectErrorContains: "invalid time off",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.filename)
+			test.Require(t, os.WriteFile(path, []byte(tc.content), 0o600) == nil)
+
+			f, err := Load(path)
+			if tc.expectErrorLocation == "" && tc.expectErrorContains == "" {
+				test.Require(t, err == nil, err)
+				test.Assert(check.Compare(t, f, tc.expected))
+
+				return
+			}
+
+			var fileErr *Error
+
+			test.Require(t, errors.As(err, &fileErr), err)
+			test.Assert(t, fileErr.Path == path)
+			test.Assert(t, strings.HasPrefix(err.Error(), filepath.Join(filepath.Dir(path), tc.expectErrorLocation)), err)
+			test.Assert(t, strings.Contains(err.Error(), tc.expectErrorContains), err)
+		})
+	}
+}
+
+func Test_File_Profile(t *testing.T) {
+	f := File{
+		Definition: Definition{
+			Timezone: "Europe/Paris",
+			Schedule: "mon-fri 09:00-18:00",
+			Holidays: []string{"2026-12-25"},
+		},
+		Profiles: map[string]Definition{
+			"client-a": {Weekly: map[string][]string{"tue": {"14:00-18:00"}}, Holidays: []string{"2026-05-01"}},
+		},
+	}
+
+	def, err := f.Profile("")
+	test.Require(t, err == nil, err)
+	test.Assert(check.Compare(t, def, f.Definition))
+
+	def, err = f.Profile("client-a")
+	test.Require(t, err == nil, err)
+	test.Assert(check.Compare(t, def, Definition{
+		Timezone: "Europe/Paris",
+		Weekly:   map[string][]string{"tue": {"14:00-18:00"}},
+		Holidays: []string{"2026-12-25", "2026-05-01"},
+	}))
+
+	_, err = f.Profile("client-b")
+	test.Assert(t, err != nil && strings.Contains(err.Error(), `schedule profile "client-b" is not defined`), err)
+}
+
+func Test_Definition_WeeklySchedule(t *testing.T) {
+	test.Assert(t, Definition{}.WeeklySchedule() == "")
+	test.Assert(t, Definition{Schedule: ",9h-18h,,,,,"}.WeeklySchedule() == ",9h-18h,,,,,")
+	test.Assert(t, Definition{Weekly: map[string][]string{
+		"saturday": {"10:00-12:00"},
+		"mon":      {"09:00-12:00", "13:00-18:00"},
+		"sun":      {},
+	}}.WeeklySchedule() == "mon 09:00-12:00,13:00-18:00; sat 10:00-12:00")
+}
@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/krostar/git-workhours/internal/workhours"
+)
+
+// Heatmap counts commits per weekday, indexed like time.Weekday, and per hour of the day.
+type Heatmap [7][24]int
+
+// Week counts the commits of an ISO week.
+type Week struct {
+	Year     int
+	Week     int
+	Commits  int
+	Overtime int
+}
+
+// Stats sums up when commits were made compared to the schedule.
+type Stats struct {
+	Heatmap Heatmap
+	Commits int
+	// Overtime is the amount of commits made outside of the schedule, exceptions and time off included.
+	Overtime int
+	// Weekend is the amount of commits made on Saturdays and Sundays.
+	Weekend int
+	// Weeks are the weeks during which commits were made, the ones with the most overtime commits first.
+	Weeks []Week
+}
+
+// Compute computes the statistics of the provided commit dates.
+// Dates are evaluated in the schedule location if it is set, in their own location otherwise.
+func Compute(schedule workhours.Schedule, dates []time.Time) Stats {
+	var stats Stats
+
+	weeks := make(map[[2]int]*Week)
+
+	for _, date := range dates {
+		if schedule.Location != nil {
+			date = date.In(schedule.Location)
+		}
+
+		stats.Commits++
+		stats.Heatmap[date.Weekday()][date.Hour()]++
+
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			stats.Weekend++
+		}
+
+		year, number := date.ISOWeek()
+
+		week, found := weeks[[2]int{year, number}]
+		if !found {
+			week = &Week{Year: year, Week: number}
+			weeks[[2]int{year, number}] = week
+		}
+
+		week.Commits++
+
+		if schedule.CurrentShift(date) == nil {
+			stats.Overtime++
+			week.Overtime++
+		}
+	}
+
+	for _, week := range weeks {
+		stats.Weeks = append(stats.Weeks, *week)
+	}
+
+	slices.SortFunc(stats.Weeks, func(a, b Week) int {
+		return cmp.Or(
+			cmp.Compare(b.Overtime, a.Overtime),
+			cmp.Compare(b.Commits, a.Commits),
+			cmp.Compare(b.Year, a.Year),
+			cmp.Compare(b.Week, a.Week),
+		)
+	})
+
+	return stats
+}
+
+// ScheduledSlots returns, for each weekday and hour of the day, whether the middle of the hour is within the weekly schedule.
+// Overnight shifts are taken into account on both days they span.
+func ScheduledSlots(weekly workhours.WeeklySchedule) [7][24]bool {
+	var slots [7][24]bool
+
+	for day := range slots {
+		for hour := range slots[day] {
+			// 2023-01-01 is a Sunday, and UTC has no daylight saving time that could skip hours
+			slots[day][hour] = weekly.CurrentShift(time.Date(2023, time.January, 1+day, hour, 30, 0, 0, time.UTC)) != nil
+		}
+	}
+
+	return slots
+}
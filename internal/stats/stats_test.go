@@ -0,0 +1,83 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/krostar/test"
+	"github.com/krostar/test/check"
+
+	"github.com/krostar/git-workhours/internal/workhours"
+)
+
+func Test_Compute(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	test.Require(t, err == nil, err)
+
+	schedule := workhours.Schedule{
+		Weekly: workhours.WeeklySchedule{
+			{},
+			{{9 * time.Hour, 18 * time.Hour}},
+			{{9 * time.Hour, 18 * time.Hour}},
+			{{9 * time.Hour, 18 * time.Hour}},
+			{{9 * time.Hour, 18 * time.Hour}},
+			{{9 * time.Hour, 18 * time.Hour}},
+			{},
+		},
+		Exceptions: []workhours.ScheduleException{{DateRange: workhours.DateRange{
+			From: workhours.Date{Year: 2026, Month: time.October, Day: 16},
+			To:   workhours.Date{Year: 2026, Month: time.October, Day: 16},
+		}}},
+		Location: paris,
+	}
+
+	stats := Compute(schedule, []time.Time{
+		time.Date(2026, 10, 12, 10, 0, 0, 0, paris),    // monday, within schedule
+		time.Date(2026, 10, 12, 8, 30, 0, 0, time.UTC), // monday 10:30 in Paris, within schedule
+		time.Date(2026, 10, 12, 21, 0, 0, 0, paris),    // monday, over time
+		time.Date(2026, 10, 16, 10, 0, 0, 0, paris),    // friday, day off
+		time.Date(2026, 10, 17, 11, 0, 0, 0, paris),    // saturday
+		time.Date(2026, 10, 19, 10, 0, 0, 0, paris),    // next monday, within schedule
+	})
+
+	var expectedHeatmap Heatmap
+	expectedHeatmap[time.Monday][10] = 3
+	expectedHeatmap[time.Monday][21] = 1
+	expectedHeatmap[time.Friday][10] = 1
+	expectedHeatmap[time.Saturday][11] = 1
+
+	test.Assert(check.Compare(t, stats, Stats{
+		Heatmap:  expectedHeatmap,
+		Commits:  6,
+		Overtime: 3,
+		Weekend:  1,
+		Weeks: []Week{
+			{Year: 2026, Week: 42, Commits: 5, Overtime: 3},
+			{Year: 2026, Week: 43, Commits: 1},
+		},
+	}))
+
+	test.Assert(check.Compare(t, Compute(schedule, nil), Stats{}))
+}
+
+func Test_ScheduledSlots(t *testing.T) {
+	slots := ScheduledSlots(workhours.WeeklySchedule{
+		{},
+		{{9 * time.Hour, 12*time.Hour + 30*time.Minute}},
+		{},
+		{},
+		{},
+		{},
+		{{22 * time.Hour, 26 * time.Hour}},
+	})
+
+	var expected [7][24]bool
+	for hour := 9; hour < 12; hour++ {
+		expected[time.Monday][hour] = true
+	}
+
+	expected[time.Saturday][22], expected[time.Saturday][23] = true, true
+	expected[time.Sunday][0], expected[time.Sunday][1] = true, true
+
+	test.Assert(check.Compare(t, slots, expected))
+}
@@ -0,0 +1,210 @@
+package timesheet
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/krostar/git-workhours/internal/workhours"
+)
+
+// Commit is a commit with its real date.
+type Commit struct {
+	Hash string
+	Date time.Time
+}
+
+// Options tunes how worked time is estimated from commits.
+type Options struct {
+	// SessionGap is the maximum duration between two commits of the same work session.
+	SessionGap time.Duration
+	// FirstCommit is the time assumed to be worked before the first commit of a work session.
+	FirstCommit time.Duration
+}
+
+// DefaultOptions returns the options used to estimate worked time by default.
+func DefaultOptions() Options {
+	return Options{SessionGap: 2 * time.Hour, FirstCommit: 30 * time.Minute}
+}
+
+// Shift groups the commits made within the same scheduled shift, or outside of any shift.
+type Shift struct {
+	// Shift is the scheduled shift the commits were made in, nil for commits made over time.
+	Shift   *workhours.WorkingShift
+	Commits []Commit
+	Worked  time.Duration
+}
+
+// Day sums up the commits of a calendar day.
+type Day struct {
+	Date      workhours.Date
+	Shifts    []Shift
+	Scheduled time.Duration
+	Worked    time.Duration
+	Overtime  time.Duration
+}
+
+// Commits returns the amount of commits made during the day.
+func (d Day) Commits() int {
+	var commits int
+	for _, shift := range d.Shifts {
+		commits += len(shift.Commits)
+	}
+
+	return commits
+}
+
+// Week sums up the days of an ISO week during which commits were made.
+type Week struct {
+	Year int
+	Week int
+	Days []Day
+	// Scheduled is the duration scheduled over the whole week, including days without commits.
+	Scheduled time.Duration
+	Worked    time.Duration
+	Overtime  time.Duration
+}
+
+// Build groups commits by week, day, and shift, and estimates the worked time of each of them.
+//
+// Commits separated by at most SessionGap belong to the same work session, the time between them is considered worked,
+// and FirstCommit is considered worked before the first commit of each session.
+// Worked time is split at shift boundaries: time within a shift is attributed to it, other time counts as overtime.
+// Commits made within a shift belong to the day the shift started, other commits, and overtime, to their calendar day in the schedule location.
+func Build(schedule workhours.Schedule, commits []Commit, opts Options) []Week {
+	commits = slices.SortedFunc(slices.Values(commits), func(a, b Commit) int { return a.Date.Compare(b.Date) })
+
+	days := make(map[workhours.Date]*Day)
+	dayOf := func(t time.Time, shift *workhours.WorkingShift) *Day {
+		if schedule.Location != nil {
+			t = t.In(schedule.Location)
+		}
+
+		if shift != nil {
+			t = shift[0]
+		}
+
+		day, found := days[workhours.DateOf(t)]
+		if !found {
+			day = &Day{Date: workhours.DateOf(t), Scheduled: scheduledOn(schedule, workhours.DateOf(t))}
+			days[day.Date] = day
+		}
+
+		return day
+	}
+
+	for i, commit := range commits {
+		from := commit.Date.Add(-opts.FirstCommit)
+		if i > 0 && commit.Date.Sub(commits[i-1].Date) <= opts.SessionGap {
+			from = commits[i-1].Date
+		}
+
+		for from.Before(commit.Date) {
+			shift, to := workedUntil(schedule, from, commit.Date)
+			dayOf(to, shift).addWorked(shift, to.Sub(from))
+			from = to
+		}
+
+		shift := schedule.CurrentShift(commit.Date)
+		dayOf(commit.Date, shift).addCommit(shift, commit)
+	}
+
+	var weeks []Week
+
+	for _, date := range slices.SortedFunc(maps.Keys(days), workhours.Date.Compare) {
+		day := days[date]
+		year, week := time.Date(date.Year, date.Month, date.Day, 0, 0, 0, 0, time.UTC).ISOWeek()
+
+		if len(weeks) == 0 || weeks[len(weeks)-1].Year != year || weeks[len(weeks)-1].Week != week {
+			weeks = append(weeks, Week{Year: year, Week: week})
+
+			monday := date.AddDays(-(int(date.Weekday()) + 6) % 7)
+			for i := range 7 {
+				weeks[len(weeks)-1].Scheduled += scheduledOn(schedule, monday.AddDays(i))
+			}
+		}
+
+		current := &weeks[len(weeks)-1]
+		current.Days = append(current.Days, *day)
+		current.Worked += day.Worked
+		current.Overtime += day.Overtime
+	}
+
+	return weeks
+}
+
+func (d *Day) addCommit(shift *workhours.WorkingShift, commit Commit) {
+	index := d.shiftIndex(shift)
+	d.Shifts[index].Commits = append(d.Shifts[index].Commits, commit)
+}
+
+func (d *Day) addWorked(shift *workhours.WorkingShift, worked time.Duration) {
+	d.Worked += worked
+	if shift == nil {
+		d.Overtime += worked
+	}
+
+	d.Shifts[d.shiftIndex(shift)].Worked += worked
+}
+
+// shiftIndex returns the index of the provided shift in the day shifts, adding it if needed.
+func (d *Day) shiftIndex(shift *workhours.WorkingShift) int {
+	isSameShift := func(s Shift) bool {
+		return (s.Shift == nil && shift == nil) || (s.Shift != nil && shift != nil && s.Shift[0].Equal(shift[0]))
+	}
+
+	if !slices.ContainsFunc(d.Shifts, isSameShift) {
+		d.Shifts = append(d.Shifts, Shift{Shift: shift})
+		slices.SortStableFunc(d.Shifts, compareShifts)
+	}
+
+	return slices.IndexFunc(d.Shifts, isSameShift)
+}
+
+// workedUntil returns the shift time worked from the provided time falls in, nil if it is over time,
+// and until when that remains the case, at most until the provided time.
+func workedUntil(schedule workhours.Schedule, from, until time.Time) (*workhours.WorkingShift, time.Time) {
+	// shifts bounds are exclusive, the time right after from tells whether from is the start of a shift
+	if shift := schedule.CurrentShift(from.Add(time.Nanosecond)); shift != nil {
+		return shift, earliest(shift[1], until)
+	}
+
+	if next := schedule.NextShift(from); next != nil {
+		return nil, earliest(next[0], until)
+	}
+
+	return nil, until
+}
+
+// earliest returns the earliest of the provided times.
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
+
+// compareShifts sorts shifts by start, commits made over time last.
+func compareShifts(a, b Shift) int {
+	switch {
+	case a.Shift == nil && b.Shift == nil:
+		return 0
+	case a.Shift == nil:
+		return 1
+	case b.Shift == nil:
+		return -1
+	default:
+		return a.Shift[0].Compare(b.Shift[0])
+	}
+}
+
+// scheduledOn returns the total duration of the shifts scheduled on the provided date.
+func scheduledOn(schedule workhours.Schedule, date workhours.Date) time.Duration {
+	var scheduled time.Duration
+	for _, shift := range schedule.ShiftsOn(date) {
+		scheduled += shift[1] - shift[0]
+	}
+
+	return scheduled
+}
@@ -0,0 +1,93 @@
+package timesheet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/krostar/test"
+	"github.com/krostar/test/check"
+
+	"github.com/krostar/git-workhours/internal/workhours"
+)
+
+func Test_Build(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	test.Require(t, err == nil, err)
+
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 10, day, hour, minute, 0, 0, paris) }
+	shift := func(day, startHour, endHour int) *workhours.WorkingShift {
+		return &workhours.WorkingShift{at(day, startHour, 0), at(day, endHour, 0)}
+	}
+
+	schedule := workhours.Schedule{
+		Weekly: workhours.WeeklySchedule{
+			{},
+			{{9 * time.Hour, 12 * time.Hour}, {13 * time.Hour, 18 * time.Hour}},
+			{{9 * time.Hour, 12 * time.Hour}, {13 * time.Hour, 18 * time.Hour}},
+			{{9 * time.Hour, 12 * time.Hour}, {13 * time.Hour, 18 * time.Hour}},
+			{{9 * time.Hour, 12 * time.Hour}, {13 * time.Hour, 18 * time.Hour}},
+			{{22 * time.Hour, 26 * time.Hour}},
+			{},
+		},
+		Location: paris,
+	}
+
+	commits := []Commit{
+		{Hash: "c3", Date: at(12, 14, 0)},
+		{Hash: "c1", Date: at(12, 9, 30)},
+		{Hash: "c2", Date: at(12, 10, 15)},
+		{Hash: "c4", Date: at(12, 19, 0)},
+		{Hash: "c5", Date: at(16, 23, 0)},
+		{Hash: "c6", Date: at(17, 1, 0)},
+		{Hash: "c7", Date: at(19, 10, 0).UTC()},
+		{Hash: "c8", Date: at(12, 17, 30)},
+	}
+
+	weeks := Build(schedule, commits, DefaultOptions())
+
+	test.Assert(check.Compare(t, weeks, []Week{
+		{
+			Year: 2026, Week: 42,
+			Days: []Day{
+				{
+					Date: workhours.Date{Year: 2026, Month: time.October, Day: 12},
+					Shifts: []Shift{
+						{Shift: shift(12, 9, 12), Commits: []Commit{commits[1], commits[2]}, Worked: 75 * time.Minute},
+						{Shift: shift(12, 13, 18), Commits: []Commit{commits[0], commits[7]}, Worked: 90 * time.Minute},
+						{Commits: []Commit{commits[3]}, Worked: 60 * time.Minute},
+					},
+					Scheduled: 8 * time.Hour,
+					Worked:    225 * time.Minute,
+					Overtime:  60 * time.Minute,
+				},
+				{
+					Date: workhours.Date{Year: 2026, Month: time.October, Day: 16},
+					Shifts: []Shift{
+						{Shift: &workhours.WorkingShift{at(16, 22, 0), at(17, 2, 0)}, Commits: []Commit{commits[4], commits[5]}, Worked: 150 * time.Minute},
+					},
+					Scheduled: 4 * time.Hour,
+					Worked:    150 * time.Minute,
+				},
+			},
+			Scheduled: 36 * time.Hour,
+			Worked:    375 * time.Minute,
+			Overtime:  60 * time.Minute,
+		},
+		{
+			Year: 2026, Week: 43,
+			Days: []Day{
+				{
+					Date:      workhours.Date{Year: 2026, Month: time.October, Day: 19},
+					Shifts:    []Shift{{Shift: shift(19, 9, 12), Commits: []Commit{commits[6]}, Worked: 30 * time.Minute}},
+					Scheduled: 8 * time.Hour,
+					Worked:    30 * time.Minute,
+				},
+			},
+			Scheduled: 36 * time.Hour,
+			Worked:    30 * time.Minute,
+		},
+	}))
+
+	test.Assert(t, weeks[0].Days[0].Commits() == 5)
+	test.Assert(t, len(Build(schedule, nil, DefaultOptions())) == 0)
+}
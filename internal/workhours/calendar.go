@@ -0,0 +1,294 @@
+package workhours
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Date represents a calendar day, independently of any time or location.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the calendar day of the provided time, in its location.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// ParseDate parses a date in the YYYY-MM-DD format.
+func ParseDate(raw string) (Date, error) {
+	t, err := time.Parse(time.DateOnly, strings.TrimSpace(raw))
+	if err != nil {
+		return Date{}, fmt.Errorf("unable to parse date %q: %w", raw, err)
+	}
+
+	return DateOf(t), nil
+}
+
+// AddDays returns the date shifted by the provided amount of days.
+func (d Date) AddDays(days int) Date {
+	return DateOf(time.Date(d.Year, d.Month, d.Day+days, 0, 0, 0, 0, time.UTC))
+}
+
+// Compare returns -1 if d is before o, +1 if d is after o, and 0 if they are the same day.
+func (d Date) Compare(o Date) int {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, time.UTC).Compare(time.Date(o.Year, o.Month, o.Day, 0, 0, 0, 0, time.UTC))
+}
+
+// Weekday returns the day of the week of the date.
+func (d Date) Weekday() time.Weekday {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, time.UTC).Weekday()
+}
+
+// String returns the date in the YYYY-MM-DD format.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// DateRange represents an inclusive range of calendar days.
+type DateRange struct {
+	From Date
+	To   Date
+}
+
+// ParseDateRange parses either a single date (YYYY-MM-DD) or an inclusive range of dates (YYYY-MM-DD..YYYY-MM-DD).
+func ParseDateRange(raw string) (DateRange, error) {
+	rawFrom, rawTo, isRange := strings.Cut(raw, "..")
+
+	from, err := ParseDate(rawFrom)
+	if err != nil {
+		return DateRange{}, err
+	}
+
+	if !isRange {
+		return DateRange{From: from, To: from}, nil
+	}
+
+	to, err := ParseDate(rawTo)
+	if err != nil {
+		return DateRange{}, err
+	}
+
+	if from.Compare(to) > 0 {
+		return DateRange{}, fmt.Errorf("date range starts after it ends: %s", raw)
+	}
+
+	return DateRange{From: from, To: to}, nil
+}
+
+// Contains returns whether the provided date is part of the range.
+func (r DateRange) Contains(d Date) bool {
+	return r.From.Compare(d) <= 0 && r.To.Compare(d) >= 0
+}
+
+// String returns a human-readable representation of the date range.
+func (r DateRange) String() string {
+	if r.From == r.To {
+		return r.From.String()
+	}
+
+	return r.From.String() + ".." + r.To.String()
+}
+
+// ScheduleException overrides the weekly schedule for a range of dates, like public holidays.
+// An exception without any shift describes non-working days.
+type ScheduleException struct {
+	DateRange
+
+	Shifts []WorkingShiftSchedule
+}
+
+// ParseScheduleException parses a raw exception into a ScheduleException.
+// The expected format is a date range, optionally followed by '=' and the shifts of the days in the range,
+// using the same shift format as ParseWeeklySchedule, eg: '2026-12-25', '2026-12-24=9h-12h', '2026-08-01..2026-08-15'.
+func ParseScheduleException(raw string) (ScheduleException, error) {
+	rawRange, rawShifts, _ := strings.Cut(strings.TrimSpace(raw), "=")
+
+	dateRange, err := ParseDateRange(rawRange)
+	if err != nil {
+		return ScheduleException{}, fmt.Errorf("invalid exception %q: %w", raw, err)
+	}
+
+	shifts, err := parseShifts(dateRange.String(), rawShifts)
+	if err != nil {
+		return ScheduleException{}, err
+	}
+
+	return ScheduleException{DateRange: dateRange, Shifts: shifts}, nil
+}
+
+// Schedule represents a weekly schedule on top of which date-specific exceptions apply.
+type Schedule struct {
+	Weekly WeeklySchedule
+	// Exceptions replace the weekly shifts of the days they contain, the last matching exception wins.
+	Exceptions []ScheduleException
+	// TimeOff are periods during which no work is expected at all, regardless of the weekly schedule or the exceptions.
+	// Unlike exceptions, time off periods are not affected by schedule inversion.
+	TimeOff []DateRange
+	// Location is the timezone in which the schedule is evaluated, if nil the location of the evaluated time is used.
+	Location *time.Location
+}
+
+// Inverted returns a Schedule with all working hours, including exceptions, inverted to represent non-working hours.
+// Time off periods are kept as is.
+func (s Schedule) Inverted() Schedule {
+	inverted := Schedule{Weekly: s.Weekly.Inverted(), TimeOff: s.TimeOff, Location: s.Location}
+
+	var boundaries []Date
+
+	for _, exception := range s.Exceptions {
+		inverted.Exceptions = append(inverted.Exceptions, ScheduleException{
+			DateRange: exception.DateRange,
+			Shifts:    invertShifts(exception.Shifts, spillover(exception.Shifts)),
+		})
+
+		boundaries = append(boundaries, exception.From, exception.To.AddDays(1))
+	}
+
+	// days on the boundaries of exceptions have been inverted assuming their previous day had the same shifts,
+	// which is wrong if the actual previous day has an overnight shift spilling differently on them
+	for _, day := range boundaries {
+		shifts, isException := s.plannedShiftsOn(day)
+		previousShifts, _ := s.plannedShiftsOn(day.AddDays(-1))
+
+		assumedPreviousShifts := shifts
+		if !isException {
+			assumedPreviousShifts = s.Weekly[day.AddDays(-1).Weekday()]
+		}
+
+		if spill := spillover(previousShifts); spill != spillover(assumedPreviousShifts) {
+			inverted.Exceptions = append(inverted.Exceptions, ScheduleException{
+				DateRange: DateRange{From: day, To: day},
+				Shifts:    invertShifts(shifts, spill),
+			})
+		}
+	}
+
+	return inverted
+}
+
+// ShiftsOn returns the shifts scheduled for the provided date, sorted by start.
+func (s Schedule) ShiftsOn(d Date) []WorkingShiftSchedule {
+	shifts, _ := s.shiftsOn(d)
+	return shifts
+}
+
+// timeOffOn returns the time off period containing the provided date, if any.
+func (s Schedule) timeOffOn(d Date) *DateRange {
+	for _, timeOff := range s.TimeOff {
+		if timeOff.Contains(d) {
+			return &timeOff
+		}
+	}
+
+	return nil
+}
+
+// shiftsOn returns the shifts scheduled for the provided date,
+// and whether they come from an exception or a time off rather than the weekly schedule.
+func (s Schedule) shiftsOn(d Date) ([]WorkingShiftSchedule, bool) {
+	if s.timeOffOn(d) != nil {
+		return nil, true
+	}
+
+	return s.plannedShiftsOn(d)
+}
+
+// plannedShiftsOn returns the shifts planned for the provided date by the weekly schedule and the exceptions,
+// regardless of time off, and whether they come from an exception.
+func (s Schedule) plannedShiftsOn(d Date) ([]WorkingShiftSchedule, bool) {
+	shifts, isException := s.Weekly[d.Weekday()], false
+
+	for _, exception := range s.Exceptions {
+		if exception.Contains(d) {
+			shifts, isException = exception.Shifts, true
+		}
+	}
+
+	return slices.SortedFunc(slices.Values(shifts), func(a, b WorkingShiftSchedule) int { return int(a[0] - b[0]) }), isException
+}
+
+// CurrentShift returns the current working shift for a given time, or nil if not within working hours.
+// Overnight shifts started the day before are taken into account.
+func (s Schedule) CurrentShift(t time.Time) *WorkingShift {
+	t = s.in(t)
+
+	for _, day := range []Date{DateOf(t), DateOf(t).AddDays(-1)} {
+		for _, schedule := range s.ShiftsOn(day) {
+			shift := schedule.At(day.Year, day.Month, day.Day, t.Location())
+			if t.After(shift[0]) && t.Before(shift[1]) {
+				return &shift
+			}
+		}
+	}
+
+	return nil
+}
+
+// in returns the provided time in the schedule location.
+func (s Schedule) in(t time.Time) time.Time {
+	if s.Location == nil {
+		return t
+	}
+
+	return t.In(s.Location)
+}
+
+// PreviousShift returns the most recent working shift that occurred before the given time.
+func (s Schedule) PreviousShift(t time.Time) *WorkingShift {
+	return s.findClosestShift(t, -1)
+}
+
+// NextShift returns the next working shift that will occur after the given time.
+func (s Schedule) NextShift(t time.Time) *WorkingShift {
+	return s.findClosestShift(t, 1)
+}
+
+// findClosestShift walks through days, starting from t's day, in the provided direction until it finds a shift.
+// The search is bounded to a week of regular days, days covered by exceptions don't count
+// in the budget so that long exceptions, like holidays spanning several weeks, can be skipped over.
+// Time off periods are jumped over at once, whatever their length.
+func (s Schedule) findClosestShift(t time.Time, direction int) *WorkingShift {
+	t = s.in(t)
+	day := DateOf(t)
+
+	for remaining := 7; remaining >= 0; day = day.AddDays(direction) {
+		if timeOff := s.timeOffOn(day); timeOff != nil {
+			day = timeOff.To
+			if direction < 0 {
+				day = timeOff.From
+			}
+
+			continue
+		}
+
+		shifts, isException := s.shiftsOn(day)
+
+		if direction < 0 {
+			slices.Reverse(shifts)
+		}
+
+		for _, schedule := range shifts {
+			shift := schedule.At(day.Year, day.Month, day.Day, t.Location())
+
+			if direction < 0 && shift[0].Before(t) && shift[1].Before(t) {
+				return &shift
+			}
+
+			if direction > 0 && shift[0].After(t) && shift[1].After(t) {
+				return &shift
+			}
+		}
+
+		if !isException {
+			remaining--
+		}
+	}
+
+	return nil
+}
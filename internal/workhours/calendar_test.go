@@ -0,0 +1,344 @@
+package workhours
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krostar/test"
+	"github.com/krostar/test/check"
+)
+
+func getScheduleWithExceptions() Schedule {
+	return Schedule{
+		Weekly: getRegularWorkhoursSchedule(),
+		Exceptions: []ScheduleException{
+			{DateRange: DateRange{From: Date{2020, time.March, 25}, To: Date{2020, time.March, 25}}},
+			{DateRange: DateRange{From: Date{2020, time.March, 26}, To: Date{2020, time.March, 26}}, Shifts: []WorkingShiftSchedule{{9 * time.Hour, 12 * time.Hour}}},
+			{DateRange: DateRange{From: Date{2020, time.April, 1}, To: Date{2020, time.April, 30}}},
+		},
+	}
+}
+
+func Test_Date(t *testing.T) {
+	d := DateOf(time.Date(2020, time.February, 28, 23, 59, 0, 0, time.UTC))
+	test.Assert(check.Compare(t, d, Date{2020, time.February, 28}))
+	test.Assert(check.Compare(t, d.AddDays(1), Date{2020, time.February, 29}))
+	test.Assert(check.Compare(t, d.AddDays(2), Date{2020, time.March, 1}))
+	test.Assert(check.Compare(t, d.AddDays(-59), Date{2019, time.December, 31}))
+	test.Assert(t, d.Compare(d.AddDays(1)) < 0 && d.Compare(d.AddDays(-1)) > 0 && d.Compare(d) == 0)
+	test.Assert(t, d.Weekday() == time.Friday)
+	test.Assert(t, d.String() == "2020-02-28")
+}
+
+func Test_ParseDateRange(t *testing.T) {
+	for name, tc := range map[string]struct {
+		repr               string
+		expected           DateRange
+		expectErrorMessage string
+	}{
+		"single date": {
+			repr:     "2026-12-25",
+			expected: DateRange{From: Date{2026, time.December, 25}, To: Date{2026, time.December, 25}},
+		},
+		"range": {
+			repr:     "2026-12-20..2027-01-03",
+			expected: DateRange{From: Date{2026, time.December, 20}, To: Date{2027, time.January, 3}},
+		},
+		"invalid date": {
+			repr:               "2026-13-25",
+			expectErrorMessage: "unable to parse date",
+		},
+		"invalid range end": {
+			repr:               "2026-12-20..",
+			expectErrorMessage: "unable to parse date",
+		},
+		"reversed range": {
+			repr:               "2027-01-03..2026-12-20",
+			expectErrorMessage: "date range starts after it ends",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dr, err := ParseDateRange(tc.repr)
+			if tc.expectErrorMessage == "" {
+				test.Assert(t, err == nil, err)
+				test.Assert(check.Compare(t, dr, tc.expected))
+				test.Assert(t, dr.String() == tc.repr)
+			} else {
+				test.Require(t, err != nil && strings.Contains(err.Error(), tc.expectErrorMessage), err)
+			}
+		})
+	}
+}
+
+func Test_ParseScheduleException(t *testing.T) {
+	for name, tc := range map[string]struct {
+		repr               string
+		expected           ScheduleException
+		expectErrorMessage string
+	}{
+		"holiday": {
+			repr: "2026-12-25",
+			expected: ScheduleException{
+				DateRange: DateRange{From: Date{2026, time.December, 25}, To: Date{2026, time.December, 25}},
+				Shifts:    []WorkingShiftSchedule{},
+			},
+		},
+		"different shifts on a range": {
+			repr: " 2026-08-01..2026-08-15=9h-12h+13h-15h",
+			expected: ScheduleException{
+				DateRange: DateRange{From: Date{2026, time.August, 1}, To: Date{2026, time.August, 15}},
+				Shifts:    []WorkingShiftSchedule{{9 * time.Hour, 12 * time.Hour}, {13 * time.Hour, 15 * time.Hour}},
+			},
+		},
+		"invalid date": {
+			repr:               "2026-12-32",
+			expectErrorMessage: "invalid exception",
+		},
+		"invalid shift": {
+			repr:               "2026-12-24=9h",
+			expectErrorMessage: "2026-12-24's shift is invalid",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			exception, err := ParseScheduleException(tc.repr)
+			if tc.expectErrorMessage == "" {
+				test.Assert(t, err == nil, err)
+				test.Assert(check.Compare(t, exception, tc.expected))
+			} else {
+				test.Require(t, err != nil && strings.Contains(err.Error(), tc.expectErrorMessage), err)
+			}
+		})
+	}
+}
+
+func Test_Schedule_Inverted(t *testing.T) {
+	inverted := getScheduleWithExceptions().Inverted()
+
+	test.Assert(check.Compare(t, inverted.Weekly, getRegularWorkhoursSchedule().Inverted()))
+	test.Assert(check.Compare(t, inverted.Exceptions, []ScheduleException{
+		{DateRange: DateRange{From: Date{2020, time.March, 25}, To: Date{2020, time.March, 25}}, Shifts: []WorkingShiftSchedule{{0, (24 * time.Hour) - 1}}},
+		{DateRange: DateRange{From: Date{2020, time.March, 26}, To: Date{2020, time.March, 26}}, Shifts: []WorkingShiftSchedule{{0, 9 * time.Hour}, {12 * time.Hour, (24 * time.Hour) - 1}}},
+		{DateRange: DateRange{From: Date{2020, time.April, 1}, To: Date{2020, time.April, 30}}, Shifts: []WorkingShiftSchedule{{0, (24 * time.Hour) - 1}}},
+	}))
+}
+
+func Test_Schedule_Inverted_overnight(t *testing.T) {
+	schedule := Schedule{
+		Weekly: getNightWorkhoursSchedule(),
+		Exceptions: []ScheduleException{
+			{DateRange: DateRange{From: Date{2024, time.July, 9}, To: Date{2024, time.July, 10}}},
+		},
+	}
+
+	inverted := schedule.Inverted()
+
+	for day, expected := range map[Date][]WorkingShiftSchedule{
+		{2024, time.July, 8}:  {{0, 22 * time.Hour}},                   // monday, no spill from sunday
+		{2024, time.July, 9}:  {{6 * time.Hour, (24 * time.Hour) - 1}}, // holiday, spill from monday
+		{2024, time.July, 10}: {{0, (24 * time.Hour) - 1}},             // holiday, no spill from the holiday
+		{2024, time.July, 11}: {{0, 22 * time.Hour}},                   // thursday, no spill from the holiday
+		{2024, time.July, 12}: {{6 * time.Hour, 22 * time.Hour}},       // friday, spill from thursday
+		{2024, time.July, 13}: {{6 * time.Hour, (24 * time.Hour) - 1}}, // saturday, spill from friday
+		{2024, time.July, 14}: {{0, (24 * time.Hour) - 1}},             // sunday
+	} {
+		t.Run(day.String(), func(t *testing.T) {
+			test.Assert(check.Compare(t, inverted.ShiftsOn(day), expected))
+		})
+	}
+}
+
+func Test_Schedule_CurrentShift(t *testing.T) {
+	for name, tc := range map[string]struct {
+		time        time.Time
+		expectShift *WorkingShift
+	}{
+		"regular day": {
+			time: time.Date(2020, time.March, 24, 14, 0, 0, 0, time.UTC),
+			expectShift: &WorkingShift{
+				time.Date(2020, time.March, 24, 8, 0, 0, 0, time.UTC),
+				time.Date(2020, time.March, 24, 18, 0, 0, 0, time.UTC),
+			},
+		},
+		"holiday": {
+			time:        time.Date(2020, time.March, 25, 14, 0, 0, 0, time.UTC),
+			expectShift: nil,
+		},
+		"day with different shifts, outside": {
+			time:        time.Date(2020, time.March, 26, 14, 0, 0, 0, time.UTC),
+			expectShift: nil,
+		},
+		"day with different shifts, inside": {
+			time: time.Date(2020, time.March, 26, 10, 0, 0, 0, time.UTC),
+			expectShift: &WorkingShift{
+				time.Date(2020, time.March, 26, 9, 0, 0, 0, time.UTC),
+				time.Date(2020, time.March, 26, 12, 0, 0, 0, time.UTC),
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			shift := getScheduleWithExceptions().CurrentShift(tc.time)
+			test.Assert(t, (shift != nil && tc.expectShift != nil) || (shift == nil && tc.expectShift == nil))
+
+			if tc.expectShift != nil && shift != nil {
+				test.Assert(compareWorkingShifts(t, *tc.expectShift, *shift))
+			}
+		})
+	}
+}
+
+func Test_Schedule_PreviousShift(t *testing.T) {
+	for name, tc := range map[string]struct {
+		time        time.Time
+		expectShift *WorkingShift
+	}{
+		"after holiday": {
+			time: time.Date(2020, time.March, 26, 8, 30, 0, 0, time.UTC),
+			expectShift: &WorkingShift{
+				time.Date(2020, time.March, 24, 8, 0, 0, 0, time.UTC),
+				time.Date(2020, time.March, 24, 18, 0, 0, 0, time.UTC),
+			},
+		},
+		"after day with different shifts": {
+			time: time.Date(2020, time.March, 26, 20, 0, 0, 0, time.UTC),
+			expectShift: &WorkingShift{
+				time.Date(2020, time.March, 26, 9, 0, 0, 0, time.UTC),
+				time.Date(2020, time.March, 26, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		"after a month of holidays": {
+			time: time.Date(2020, time.May, 1, 7, 0, 0, 0, time.UTC),
+			expectShift: &WorkingShift{
+				time.Date(2020, time.March, 31, 8, 0, 0, 0, time.UTC),
+				time.Date(2020, time.March, 31, 18, 0, 0, 0, time.UTC),
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			shift := getScheduleWithExceptions().PreviousShift(tc.time)
+			test.Assert(t, (shift != nil && tc.expectShift != nil) || (shift == nil && tc.expectShift == nil))
+
+			if tc.expectShift != nil && shift != nil {
+				test.Assert(compareWorkingShifts(t, *tc.expectShift, *shift))
+			}
+		})
+	}
+}
+
+func Test_Schedule_NextShift(t *testing.T) {
+	for name, tc := range map[string]struct {
+		emptyWeek   bool
+		time        time.Time
+		expectShift *WorkingShift
+	}{
+		"before holiday": {
+			time: time.Date(2020, time.March, 24, 20, 0, 0, 0, time.UTC),
+			expectShift: &WorkingShift{
+				time.Date(2020, time.March, 26, 9, 0, 0, 0, time.UTC),
+				time.Date(2020, time.March, 26, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		"before a month of holidays": {
+			time: time.Date(2020, time.March, 31, 20, 0, 0, 0, time.UTC),
+			expectShift: &WorkingShift{
+				time.Date(2020, time.May, 1, 8, 0, 0, 0, time.UTC),
+				time.Date(2020, time.May, 1, 18, 0, 0, 0, time.UTC),
+			},
+		},
+		"no working days at all": {
+			emptyWeek:   true,
+			time:        time.Date(2020, time.March, 31, 20, 0, 0, 0, time.UTC),
+			expectShift: nil,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			schedule := getScheduleWithExceptions()
+			if tc.emptyWeek {
+				schedule.Weekly = WeeklySchedule{}
+			}
+
+			shift := schedule.NextShift(tc.time)
+			test.Assert(t, (shift != nil && tc.expectShift != nil) || (shift == nil && tc.expectShift == nil))
+
+			if tc.expectShift != nil && shift != nil {
+				test.Assert(compareWorkingShifts(t, *tc.expectShift, *shift))
+			}
+		})
+	}
+}
+
+func Test_Schedule_TimeOff(t *testing.T) {
+	schedule := Schedule{
+		Weekly:  getRegularWorkhoursSchedule(),
+		TimeOff: []DateRange{{From: Date{2026, time.December, 19}, To: Date{2027, time.January, 3}}},
+	}
+
+	t.Run("current shift", func(t *testing.T) {
+		test.Assert(t, schedule.CurrentShift(time.Date(2026, time.December, 22, 10, 0, 0, 0, time.UTC)) == nil)
+		test.Assert(t, schedule.Inverted().CurrentShift(time.Date(2026, time.December, 22, 10, 0, 0, 0, time.UTC)) == nil)
+		test.Assert(t, schedule.Inverted().CurrentShift(time.Date(2026, time.December, 18, 20, 0, 0, 0, time.UTC)) != nil)
+	})
+
+	t.Run("previous shift", func(t *testing.T) {
+		shift := schedule.PreviousShift(time.Date(2027, time.January, 4, 7, 0, 0, 0, time.UTC))
+		test.Require(t, shift != nil)
+		test.Assert(compareWorkingShifts(t, WorkingShift{
+			time.Date(2026, time.December, 18, 8, 0, 0, 0, time.UTC),
+			time.Date(2026, time.December, 18, 18, 0, 0, 0, time.UTC),
+		}, *shift))
+	})
+
+	t.Run("next shift", func(t *testing.T) {
+		shift := schedule.NextShift(time.Date(2026, time.December, 18, 20, 0, 0, 0, time.UTC))
+		test.Require(t, shift != nil)
+		test.Assert(compareWorkingShifts(t, WorkingShift{
+			time.Date(2027, time.January, 4, 8, 0, 0, 0, time.UTC),
+			time.Date(2027, time.January, 4, 18, 0, 0, 0, time.UTC),
+		}, *shift))
+	})
+
+	t.Run("long time off", func(t *testing.T) {
+		schedule := Schedule{
+			Weekly:  getRegularWorkhoursSchedule(),
+			TimeOff: []DateRange{{From: Date{2020, time.January, 1}, To: Date{2029, time.December, 31}}},
+		}
+
+		shift := schedule.PreviousShift(time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC))
+		test.Require(t, shift != nil)
+		test.Assert(compareWorkingShifts(t, WorkingShift{
+			time.Date(2019, time.December, 31, 8, 0, 0, 0, time.UTC),
+			time.Date(2019, time.December, 31, 18, 0, 0, 0, time.UTC),
+		}, *shift))
+	})
+}
+
+func Test_Schedule_Location(t *testing.T) {
+	office := time.FixedZone("office", 2*60*60)
+	schedule := Schedule{Weekly: getRegularWorkhoursSchedule(), Location: office}
+
+	t.Run("current shift", func(t *testing.T) {
+		// 17h30 UTC is 19h30 at the office
+		test.Assert(t, getRegularWorkhoursSchedule().CurrentShift(time.Date(2020, time.March, 27, 17, 30, 0, 0, time.UTC)) != nil)
+		test.Assert(t, schedule.CurrentShift(time.Date(2020, time.March, 27, 17, 30, 0, 0, time.UTC)) == nil)
+		test.Assert(t, schedule.Inverted().CurrentShift(time.Date(2020, time.March, 27, 17, 30, 0, 0, time.UTC)) != nil)
+	})
+
+	t.Run("previous shift", func(t *testing.T) {
+		shift := schedule.PreviousShift(time.Date(2020, time.March, 27, 17, 30, 0, 0, time.UTC))
+		test.Require(t, shift != nil)
+		test.Assert(compareWorkingShifts(t, WorkingShift{
+			time.Date(2020, time.March, 27, 8, 0, 0, 0, office),
+			time.Date(2020, time.March, 27, 18, 0, 0, 0, office),
+		}, *shift))
+		test.Assert(t, shift[0].Location() == office)
+	})
+
+	t.Run("next shift", func(t *testing.T) {
+		shift := schedule.NextShift(time.Date(2020, time.March, 30, 17, 0, 0, 0, time.UTC))
+		test.Require(t, shift != nil)
+		test.Assert(compareWorkingShifts(t, WorkingShift{
+			time.Date(2020, time.March, 31, 8, 0, 0, 0, office),
+			time.Date(2020, time.March, 31, 18, 0, 0, 0, office),
+		}, *shift))
+	})
+}
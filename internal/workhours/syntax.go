@@ -0,0 +1,323 @@
+package workhours
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SyntaxError describes a mistake in a schedule written with the human-friendly syntax.
+type SyntaxError struct {
+	// Column is the position of the mistake in the schedule, starting at 1.
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Column, e.Msg)
+}
+
+// isHumanSchedule returns whether the schedule is written with the human-friendly syntax, which starts with a day name,
+// while the duration format starts with a separator or a duration.
+func isHumanSchedule(raw string) bool {
+	trimmed := strings.TrimSpace(raw)
+	return trimmed != "" && unicode.IsLetter([]rune(trimmed)[0])
+}
+
+// parseHumanWeeklySchedule parses a schedule like 'mon-fri 09:00-12:00,13:00-18:00; sat 10:00-12:00', made of ';'
+// separated entries of days, or ranges of days, followed by their shifts. Days missing from the schedule are not worked.
+func parseHumanWeeklySchedule(raw string) (WeeklySchedule, error) {
+	p := scheduleParser{raw: raw}
+
+	var (
+		schedule   WeeklySchedule
+		scheduled  [7]bool
+		dayColumns [7]int
+	)
+
+	for {
+		p.skipSpaces()
+
+		if p.done() {
+			break
+		}
+
+		if p.peek() == ';' {
+			p.pos++
+			continue
+		}
+
+		days, err := p.parseDays()
+		if err != nil {
+			return WeeklySchedule{}, err
+		}
+
+		shiftsColumn := p.column()
+
+		shifts, err := p.parseShifts()
+		if err != nil {
+			return WeeklySchedule{}, err
+		}
+
+		for _, day := range days {
+			if scheduled[day.weekday] {
+				return WeeklySchedule{}, p.errorAt(day.pos, "%s is already scheduled", day.weekday)
+			}
+
+			scheduled[day.weekday] = true
+			dayColumns[day.weekday] = shiftsColumn
+			schedule[day.weekday] = shifts
+		}
+
+		p.skipSpaces()
+
+		if !p.done() && p.peek() != ';' {
+			return WeeklySchedule{}, p.errorf("expected ';' before the next days, got %q", p.peekRune())
+		}
+	}
+
+	for wd := range schedule {
+		if schedule[wd] == nil {
+			schedule[wd] = []WorkingShiftSchedule{}
+		}
+	}
+
+	if wd, err := validateWeeklySchedule(schedule); err != nil {
+		if wd < 0 {
+			return WeeklySchedule{}, err
+		}
+
+		return WeeklySchedule{}, &SyntaxError{Column: dayColumns[wd], Msg: err.Error()}
+	}
+
+	return schedule, nil
+}
+
+type scheduleParser struct {
+	raw string
+	pos int
+}
+
+type scheduledDay struct {
+	weekday time.Weekday
+	pos     int
+}
+
+// parseDays parses a ',' separated list of days or ranges of days, like 'mon-wed,fri'.
+func (p *scheduleParser) parseDays() ([]scheduledDay, error) {
+	var days []scheduledDay
+
+	for {
+		p.skipSpaces()
+
+		from, fromPos, err := p.parseDay()
+		if err != nil {
+			return nil, err
+		}
+
+		to := from
+
+		p.skipSpaces()
+
+		if !p.done() && p.peek() == '-' {
+			p.pos++
+			p.skipSpaces()
+
+			if to, _, err = p.parseDay(); err != nil {
+				return nil, err
+			}
+		}
+
+		for day := from; ; day = (day + 1) % 7 {
+			days = append(days, scheduledDay{weekday: day, pos: fromPos})
+			if day == to {
+				break
+			}
+		}
+
+		p.skipSpaces()
+
+		if p.done() || p.peek() != ',' {
+			break
+		}
+
+		p.pos++
+	}
+
+	if p.done() || p.peek() == ';' {
+		return nil, p.errorf("expected shifts like 09:00-18:00 after the days")
+	}
+
+	return days, nil
+}
+
+// parseDay parses a day name, either abbreviated like 'mon', or in full like 'monday'.
+func (p *scheduleParser) parseDay() (time.Weekday, int, error) {
+	start := p.pos
+
+	for !p.done() && p.peek() < utf8.RuneSelf && unicode.IsLetter(rune(p.peek())) {
+		p.pos++
+	}
+
+	name := strings.ToLower(p.raw[start:p.pos])
+	if name == "" {
+		return 0, start, p.errorAt(start, "expected a day like mon, got %q", p.peekRune())
+	}
+
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		full := strings.ToLower(wd.String())
+		if name == full || name == full[:3] {
+			return wd, start, nil
+		}
+	}
+
+	return 0, start, p.errorAt(start, "unknown day %q, expected one of sun, mon, tue, wed, thu, fri, sat", name)
+}
+
+// parseShifts parses a ',' separated list of shifts, like '09:00-12:00,13:00-18:00'.
+// A shift ending before it starts is an overnight shift, ending on the following day.
+func (p *scheduleParser) parseShifts() ([]WorkingShiftSchedule, error) {
+	var shifts []WorkingShiftSchedule
+
+	for {
+		p.skipSpaces()
+
+		shiftPos := p.pos
+
+		start, err := p.parseTime(false)
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpaces()
+
+		if p.done() || p.peek() != '-' {
+			return nil, p.errorf("expected '-' followed by the end of the shift, got %q", p.peekRune())
+		}
+
+		p.pos++
+		p.skipSpaces()
+
+		end, err := p.parseTime(true)
+		if err != nil {
+			return nil, err
+		}
+
+		if start > end {
+			end += 24 * time.Hour
+		}
+
+		if len(shifts) > 0 && shifts[len(shifts)-1][1] > start {
+			return nil, p.errorAt(shiftPos, "shift overlaps with, or is before, the previous shift")
+		}
+
+		shifts = append(shifts, WorkingShiftSchedule{start, end})
+
+		p.skipSpaces()
+
+		if p.done() || p.peek() != ',' {
+			return shifts, nil
+		}
+
+		p.pos++
+	}
+}
+
+// parseTime parses a time of the day like '09:00' or '9:30', '24:00' being allowed for the end of a shift.
+func (p *scheduleParser) parseTime(isEnd bool) (time.Duration, error) {
+	start := p.pos
+
+	hours, hoursDigits := p.parseNumber()
+	if hoursDigits == 0 || hoursDigits > 2 {
+		return 0, p.errorAt(start, "expected a time like 09:00, got %q", p.wordAt(start))
+	}
+
+	if p.done() || p.peek() != ':' {
+		return 0, p.errorf("expected ':' between hours and minutes, got %q", p.peekRune())
+	}
+
+	p.pos++
+
+	minutesPos := p.pos
+
+	minutes, minutesDigits := p.parseNumber()
+	if minutesDigits != 2 {
+		return 0, p.errorAt(minutesPos, "expected minutes on two digits, got %q", p.wordAt(minutesPos))
+	}
+
+	switch {
+	case minutes > 59:
+		return 0, p.errorAt(minutesPos, "minutes must be less than 60, got %02d", minutes)
+	case isEnd && hours == 24 && minutes == 0:
+	case hours > 23:
+		return 0, p.errorAt(start, "hours must be less than 24, got %d", hours)
+	}
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
+
+func (p *scheduleParser) parseNumber() (int, int) {
+	var n, digits int
+
+	for !p.done() && p.peek() >= '0' && p.peek() <= '9' {
+		n = n*10 + int(p.peek()-'0')
+		digits++
+		p.pos++
+	}
+
+	return n, digits
+}
+
+func (p *scheduleParser) skipSpaces() {
+	for !p.done() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+func (p *scheduleParser) done() bool { return p.pos >= len(p.raw) }
+
+func (p *scheduleParser) peek() byte { return p.raw[p.pos] }
+
+// peekRune returns the next character, or a description of the end of the schedule, to be used in errors.
+func (p *scheduleParser) peekRune() string {
+	return p.runeAt(p.pos)
+}
+
+func (p *scheduleParser) runeAt(pos int) string {
+	if pos >= len(p.raw) {
+		return "end of schedule"
+	}
+
+	r, _ := utf8.DecodeRuneInString(p.raw[pos:])
+
+	return string(r)
+}
+
+// wordAt returns the characters from pos until the next separator, to be used in errors.
+func (p *scheduleParser) wordAt(pos int) string {
+	end := pos
+	for end < len(p.raw) && !strings.ContainsRune(" \t,;-", rune(p.raw[end])) {
+		end++
+	}
+
+	if end == pos {
+		return p.runeAt(pos)
+	}
+
+	return p.raw[pos:end]
+}
+
+// column returns the column of the current position, starting at 1.
+func (p *scheduleParser) column() int {
+	return utf8.RuneCountInString(p.raw[:p.pos]) + 1
+}
+
+func (p *scheduleParser) errorf(format string, args ...any) error {
+	return p.errorAt(p.pos, format, args...)
+}
+
+func (p *scheduleParser) errorAt(pos int, format string, args ...any) error {
+	return &SyntaxError{Column: utf8.RuneCountInString(p.raw[:pos]) + 1, Msg: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,122 @@
+package workhours
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/krostar/test"
+	"github.com/krostar/test/check"
+)
+
+func Test_parseHumanWeeklySchedule(t *testing.T) {
+	officeDay := []WorkingShiftSchedule{{9 * time.Hour, 12 * time.Hour}, {13 * time.Hour, 18 * time.Hour}}
+
+	for name, tc := range map[string]struct {
+		raw            string
+		expected       WeeklySchedule
+		expectedColumn int
+		expectedMsg    string
+	}{
+		"days range and multiple entries": {
+			raw:      "mon-fri 09:00-12:00,13:00-18:00; sat 10:00-12:00",
+			expected: WeeklySchedule{{}, officeDay, officeDay, officeDay, officeDay, officeDay, {{10 * time.Hour, 12 * time.Hour}}},
+		},
+		"days list, full names, spaces, and case": {
+			raw:      " Monday , WED,sat - sun 9:00 - 12:00 , 13:00-18:00 ;",
+			expected: WeeklySchedule{officeDay, officeDay, {}, officeDay, {}, {}, officeDay},
+		},
+		"wrapping days range": {
+			raw:      "fri-mon 10:00-11:00",
+			expected: WeeklySchedule{{{10 * time.Hour, 11 * time.Hour}}, {{10 * time.Hour, 11 * time.Hour}}, {}, {}, {}, {{10 * time.Hour, 11 * time.Hour}}, {{10 * time.Hour, 11 * time.Hour}}},
+		},
+		"overnight shift and midnight": {
+			raw:      "mon 22:00-06:00; tue 18:00-24:00",
+			expected: WeeklySchedule{{}, {{22 * time.Hour, 30 * time.Hour}}, {{18 * time.Hour, 24 * time.Hour}}, {}, {}, {}, {}},
+		},
+		"unknown day": {
+			raw:            "mon-fry 09:00-18:00",
+			expectedColumn: 5,
+			expectedMsg:    `unknown day "fry", expected one of sun, mon, tue, wed, thu, fri, sat`,
+		},
+		"missing shifts": {
+			raw:            "mon; tue 09:00-18:00",
+			expectedColumn: 4,
+			expectedMsg:    "expected shifts like 09:00-18:00 after the days",
+		},
+		"invalid time": {
+			raw:            "mon 9h-18h",
+			expectedColumn: 6,
+			expectedMsg:    `expected ':' between hours and minutes, got "h"`,
+		},
+		"invalid minutes": {
+			raw:            "mon 09:00-18:5",
+			expectedColumn: 14,
+			expectedMsg:    `expected minutes on two digits, got "5"`,
+		},
+		"minutes out of range": {
+			raw:            "mon 09:60-18:00",
+			expectedColumn: 8,
+			expectedMsg:    "minutes must be less than 60, got 60",
+		},
+		"hours out of range": {
+			raw:            "mon 09:00-25:00",
+			expectedColumn: 11,
+			expectedMsg:    "hours must be less than 24, got 25",
+		},
+		"midnight start": {
+			raw:            "mon 24:00-01:00",
+			expectedColumn: 5,
+			expectedMsg:    "hours must be less than 24, got 24",
+		},
+		"missing shift end": {
+			raw:            "mon 09:00",
+			expectedColumn: 10,
+			expectedMsg:    `expected '-' followed by the end of the shift, got "end of schedule"`,
+		},
+		"unsorted shifts": {
+			raw:            "mon 13:00-18:00,09:00-12:00",
+			expectedColumn: 17,
+			expectedMsg:    "shift overlaps with, or is before, the previous shift",
+		},
+		"missing entries separator": {
+			raw:            "mon 09:00-18:00 tue 09:00-18:00",
+			expectedColumn: 17,
+			expectedMsg:    `expected ';' before the next days, got "t"`,
+		},
+		"day scheduled twice": {
+			raw:            "mon-fri 09:00-18:00; wed 10:00-11:00",
+			expectedColumn: 22,
+			expectedMsg:    "Wednesday is already scheduled",
+		},
+		"overnight shift overlaps next day": {
+			raw:            "mon 22:00-06:00; tue 05:00-12:00",
+			expectedColumn: 5,
+			expectedMsg:    "Monday's last shift overlaps with Tuesday's first shift",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			test.Assert(t, isHumanSchedule(tc.raw))
+
+			ws, err := ParseWeeklySchedule(tc.raw)
+			if tc.expectedMsg == "" {
+				test.Require(t, err == nil, err)
+				test.Assert(check.Compare(t, ws, tc.expected))
+
+				return
+			}
+
+			var syntaxErr *SyntaxError
+
+			test.Require(t, errors.As(err, &syntaxErr), err)
+			test.Assert(check.Compare(t, *syntaxErr, SyntaxError{Column: tc.expectedColumn, Msg: tc.expectedMsg}))
+		})
+	}
+}
+
+func Test_isHumanSchedule(t *testing.T) {
+	test.Assert(t, !isHumanSchedule(",9h-18h,9h-18h,9h-18h,9h-18h,9h-18h,"))
+	test.Assert(t, !isHumanSchedule("9h-18h,9h-18h,9h-18h,9h-18h,9h-18h,9h-18h,9h-18h"))
+	test.Assert(t, !isHumanSchedule("  "))
+	test.Assert(t, isHumanSchedule("  mon 09:00-18:00"))
+}
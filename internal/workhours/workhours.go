@@ -3,7 +3,6 @@ package workhours
 import (
 	"errors"
 	"fmt"
-	"slices"
 	"strings"
 	"time"
 )
@@ -11,8 +10,14 @@ import (
 // WeeklySchedule represents a work schedule for a full week, with each day containing multiple working shifts.
 type WeeklySchedule [7][]WorkingShiftSchedule
 
-// ParseWeeklySchedule parses a raw schedule string into a WeeklySchedule.
+// ParseWeeklySchedule parses a raw schedule string into a WeeklySchedule. The schedule is either a comma-separated list
+// of 7 days of shifts, starting on Sunday, like ',9h-12h+13h-18h,9h-18h,,,,', or written with the human-friendly syntax,
+// like 'mon-fri 09:00-12:00,13:00-18:00; sat 10:00-12:00', which is detected by its leading day name.
 func ParseWeeklySchedule(raw string) (WeeklySchedule, error) {
+	if isHumanSchedule(raw) {
+		return parseHumanWeeklySchedule(raw)
+	}
+
 	week := strings.Split(raw, ",")
 	if len(week) != 7 {
 		return WeeklySchedule{}, fmt.Errorf("expected a full week schedule: %s", raw)
@@ -21,157 +26,169 @@ func ParseWeeklySchedule(raw string) (WeeklySchedule, error) {
 	var schedule WeeklySchedule
 
 	for wd, day := range week {
-		if day == "" {
-			schedule[wd] = []WorkingShiftSchedule{}
-			continue
+		shifts, err := parseShifts(time.Weekday(wd).String(), day)
+		if err != nil {
+			return schedule, err
 		}
 
-		var previous *WorkingShiftSchedule
-
-		for shift := range strings.SplitSeq(day, "+") {
-			s := strings.Split(shift, "-")
-			if len(s) != 2 {
-				return schedule, fmt.Errorf("%s's shift is invalid: %s", time.Weekday(wd).String(), day)
-			}
-
-			start, errStart := time.ParseDuration(s[0])
-			end, errEnd := time.ParseDuration(s[1])
-
-			if err := errors.Join(errStart, errEnd); err != nil {
-				return schedule, fmt.Errorf("unable to parse %s's shift %s: %w", time.Weekday(wd).String(), day, err)
-			}
-
-			if start > end {
-				return schedule, fmt.Errorf("%s's shift starts after it ends: %s", time.Weekday(wd).String(), shift)
-			}
+		schedule[wd] = shifts
+	}
 
-			if previous != nil && previous[1] > start {
-				return schedule, fmt.Errorf("%s's shifts are not correctly sorted: %s", time.Weekday(wd).String(), shift)
-			}
+	if _, err := validateWeeklySchedule(schedule); err != nil {
+		return WeeklySchedule{}, err
+	}
 
-			if end >= (24 * time.Hour) {
-				return schedule, fmt.Errorf("end must be less to 24h: %s", end)
-			}
+	return schedule, nil
+}
 
-			previous = &WorkingShiftSchedule{start, end}
-			schedule[wd] = append(schedule[wd], *previous)
+// validateWeeklySchedule checks that overnight shifts don't overlap with the following day, and that the schedule
+// isn't empty. It returns the day at fault, or -1 if the whole schedule is.
+func validateWeeklySchedule(schedule WeeklySchedule) (int, error) {
+	for wd, shifts := range schedule {
+		next := schedule[(wd+1)%len(schedule)]
+		if spill := spillover(shifts); len(next) > 0 && spill > next[0][0] {
+			return wd, fmt.Errorf("%s's last shift overlaps with %s's first shift", time.Weekday(wd).String(), time.Weekday((wd+1)%len(schedule)).String())
 		}
 	}
 
 	for _, shifts := range schedule {
 		if len(shifts) > 0 {
-			return schedule, nil
+			return 0, nil
 		}
 	}
 
-	return WeeklySchedule{}, errors.New("schedule is empty")
+	return -1, errors.New("schedule is empty")
 }
 
-// Inverted returns a WeeklySchedule with all working hours inverted to represent non-working hours.
-func (ws WeeklySchedule) Inverted() WeeklySchedule {
-	var inverted WeeklySchedule
+// parseShifts parses a '+' separated list of shifts, name is used to give context in errors.
+// A shift ending before it starts is an overnight shift, ending on the following day.
+func parseShifts(name, raw string) ([]WorkingShiftSchedule, error) {
+	shifts := []WorkingShiftSchedule{}
 
-	eod := (24 * time.Hour) - 1
+	if raw == "" {
+		return shifts, nil
+	}
 
-	for day, shifts := range ws {
-		if len(shifts) == 0 {
-			inverted[day] = []WorkingShiftSchedule{{0, eod}}
-			continue
+	var previous *WorkingShiftSchedule
+
+	for shift := range strings.SplitSeq(raw, "+") {
+		s := strings.Split(shift, "-")
+		if len(s) != 2 {
+			return nil, fmt.Errorf("%s's shift is invalid: %s", name, raw)
 		}
 
-		for i := range shifts {
-			if shifts[i][0] <= 0 {
-				continue
-			}
+		start, errStart := time.ParseDuration(s[0])
+		end, errEnd := time.ParseDuration(s[1])
 
-			if i == 0 {
-				inverted[day] = append(inverted[day], WorkingShiftSchedule{0, shifts[i][0]})
-				continue
-			}
+		if err := errors.Join(errStart, errEnd); err != nil {
+			return nil, fmt.Errorf("unable to parse %s's shift %s: %w", name, raw, err)
+		}
 
-			if shifts[i-1][1] >= eod {
-				continue
-			}
+		if start >= (24 * time.Hour) {
+			return nil, fmt.Errorf("start must be less to 24h: %s", start)
+		}
 
-			inverted[day] = append(inverted[day], WorkingShiftSchedule{shifts[i-1][1], shifts[i][0]})
+		if end >= (24 * time.Hour) {
+			return nil, fmt.Errorf("end must be less to 24h: %s", end)
 		}
 
-		if shifts[len(shifts)-1][1] < eod {
-			inverted[day] = append(inverted[day], WorkingShiftSchedule{shifts[len(shifts)-1][1], eod})
+		if start > end {
+			end += 24 * time.Hour
 		}
 
-		if inverted[day] == nil {
-			inverted[day] = []WorkingShiftSchedule{}
+		if previous != nil && previous[1] > start {
+			return nil, fmt.Errorf("%s's shifts are not correctly sorted: %s", name, shift)
 		}
+
+		previous = &WorkingShiftSchedule{start, end}
+		shifts = append(shifts, *previous)
+	}
+
+	return shifts, nil
+}
+
+// Inverted returns a WeeklySchedule with all working hours inverted to represent non-working hours.
+// Overnight shifts are taken into account on both days they span, the inverted schedule never contains overnight shifts.
+func (ws WeeklySchedule) Inverted() WeeklySchedule {
+	var inverted WeeklySchedule
+
+	for day, shifts := range ws {
+		inverted[day] = invertShifts(shifts, spillover(ws[(day+len(ws)-1)%len(ws)]))
 	}
 
 	return inverted
 }
 
-// CurrentShift returns the current working shift for a given time, or nil if not within working hours.
-func (ws WeeklySchedule) CurrentShift(t time.Time) *WorkingShift {
-	year, month, day := t.Date()
+// invertShifts returns the complement of the provided shifts within a day,
+// the beginning of the day until spill being already worked by the previous day's overnight shift.
+func invertShifts(shifts []WorkingShiftSchedule, spill time.Duration) []WorkingShiftSchedule {
+	eod := (24 * time.Hour) - 1
+	inverted := []WorkingShiftSchedule{}
+	cursor := spill
 
-	for _, schedule := range ws[t.Weekday()] {
-		shift := schedule.At(year, month, day, t.Location())
-		if t.After(shift[0]) && t.Before(shift[1]) {
-			return &shift
+	for _, shift := range shifts {
+		if shift[0] > cursor {
+			inverted = append(inverted, WorkingShiftSchedule{cursor, shift[0]})
 		}
+
+		cursor = max(cursor, shift[1])
 	}
 
-	return nil
-}
+	if cursor < eod {
+		inverted = append(inverted, WorkingShiftSchedule{cursor, eod})
+	}
 
-// PreviousShift returns the most recent working shift that occurred before the given time.
-func (ws WeeklySchedule) PreviousShift(t time.Time) *WorkingShift {
-	return ws.findClosestShift(t,
-		func(a, b WorkingShiftSchedule) int { return int(a[0] - b[0]) },
-		func(shift WorkingShift, t time.Time) bool { return shift[0].Before(t) && shift[1].Before(t) },
-		func(year int, month time.Month, day int) time.Time {
-			return time.Date(year, month, day, 0, 0, 0, 0, t.Location()).Add(-1)
-		},
-		7,
-	)
+	return inverted
 }
 
-// NextShift returns the next working shift that will occur after the given time.
-func (ws WeeklySchedule) NextShift(t time.Time) *WorkingShift {
-	return ws.findClosestShift(t,
-		func(a, b WorkingShiftSchedule) int { return int(b[0] - a[0]) },
-		func(shift WorkingShift, t time.Time) bool { return shift[1].After(t) && shift[0].After(t) },
-		func(year int, month time.Month, day int) time.Time {
-			return time.Date(year, month, day, 0, 0, 0, 0, t.Location()).AddDate(0, 0, 1)
-		},
-		7,
-	)
-}
+// spillover returns how long the provided shifts spill over the following day.
+func spillover(shifts []WorkingShiftSchedule) time.Duration {
+	var spill time.Duration
 
-func (ws WeeklySchedule) findClosestShift(t time.Time, sortShifts func(WorkingShiftSchedule, WorkingShiftSchedule) int, isShiftValid func(shift WorkingShift, t time.Time) bool, nextTime func(year int, month time.Month, day int) time.Time, iterations int8) *WorkingShift {
-	var closest *WorkingShift
+	for _, shift := range shifts {
+		spill = max(spill, shift[1]-(24*time.Hour))
+	}
 
-	year, month, day := t.Date()
+	return spill
+}
 
-	shifts := make([]WorkingShiftSchedule, len(ws[t.Weekday()]))
-	copy(shifts, ws[t.Weekday()])
-	slices.SortFunc(shifts, sortShifts)
+// String returns the schedule in the format ParseWeeklySchedule expects, eg: ',8h-12h+13h-18h,9h-18h,,,,'.
+func (ws WeeklySchedule) String() string {
+	days := make([]string, len(ws))
+	for day, shifts := range ws {
+		days[day] = formatShifts(shifts)
+	}
 
-	for _, schedule := range shifts {
-		shift := schedule.At(year, month, day, t.Location())
-		if !isShiftValid(shift, t) {
-			break
-		}
+	return strings.Join(days, ",")
+}
 
-		closest = &shift
+// formatShifts returns the shifts in the '+' separated format parseShifts expects.
+func formatShifts(shifts []WorkingShiftSchedule) string {
+	raw := make([]string, len(shifts))
+	for i, shift := range shifts {
+		raw[i] = shift.String()
 	}
 
-	if closest == nil && iterations > 0 {
-		closest = ws.findClosestShift(nextTime(year, month, day), sortShifts, isShiftValid, nextTime, iterations-1)
-	}
+	return strings.Join(raw, "+")
+}
+
+// CurrentShift returns the current working shift for a given time, or nil if not within working hours.
+func (ws WeeklySchedule) CurrentShift(t time.Time) *WorkingShift {
+	return Schedule{Weekly: ws}.CurrentShift(t)
+}
 
-	return closest
+// PreviousShift returns the most recent working shift that occurred before the given time.
+func (ws WeeklySchedule) PreviousShift(t time.Time) *WorkingShift {
+	return Schedule{Weekly: ws}.PreviousShift(t)
+}
+
+// NextShift returns the next working shift that will occur after the given time.
+func (ws WeeklySchedule) NextShift(t time.Time) *WorkingShift {
+	return Schedule{Weekly: ws}.NextShift(t)
 }
 
 // WorkingShiftSchedule represents a single working shift defined by start and end durations from midnight.
+// The end of an overnight shift is more than 24h after midnight.
 type WorkingShiftSchedule [2]time.Duration
 
 // At converts a WorkingShiftSchedule to a concrete WorkingShift for a specific date and timezone.
@@ -182,6 +199,31 @@ func (ws WorkingShiftSchedule) At(year int, month time.Month, day int, tz *time.
 	}
 }
 
+// String returns the shift in the format ParseWeeklySchedule expects, eg: '9h-12h30m', or '22h-6h' for overnight shifts.
+func (ws WorkingShiftSchedule) String() string {
+	return FormatDuration(ws[0]) + "-" + FormatDuration(ws[1]%(24*time.Hour))
+}
+
+// MarshalText implements encoding.TextMarshaler, shifts are encoded using their String representation.
+func (ws WorkingShiftSchedule) MarshalText() ([]byte, error) {
+	return []byte(ws.String()), nil
+}
+
+// FormatDuration formats durations without their trailing zero units, eg: '9h' instead of '9h0m0s'.
+func FormatDuration(d time.Duration) string {
+	raw := d.String()
+
+	if strings.HasSuffix(raw, "m0s") {
+		raw = strings.TrimSuffix(raw, "0s")
+	}
+
+	if strings.HasSuffix(raw, "h0m") {
+		raw = strings.TrimSuffix(raw, "0m")
+	}
+
+	return raw
+}
+
 // WorkingShift represents a concrete working shift with specific start and end times.
 type WorkingShift [2]time.Time
 
@@ -34,6 +34,18 @@ func getCustomWorkhoursSchedule() WeeklySchedule {
 	}
 }
 
+func getNightWorkhoursSchedule() WeeklySchedule {
+	return WeeklySchedule{
+		{},
+		{{22 * time.Hour, 30 * time.Hour}},
+		{{22 * time.Hour, 30 * time.Hour}},
+		{{22 * time.Hour, 30 * time.Hour}},
+		{{22 * time.Hour, 30 * time.Hour}},
+		{{22 * time.Hour, 30 * time.Hour}},
+		{},
+	}
+}
+
 func compareWorkingShifts(t test.TestingT, want, got WorkingShift) (test.TestingT, bool, string) {
 	same := got[0].Equal(want[0]) && got[1].Equal(want[1])
 
@@ -71,9 +83,25 @@ func Test_ParseWeeklySchedule(t *testing.T) {
 			repr:               ",,,9j-18h,,,",
 			expectErrorMessage: "unable to parse Wednesday's shift",
 		},
-		"impossible shift": {
-			repr:               ",,,12h-9h,,,",
-			expectErrorMessage: "Wednesday's shift starts after it ends",
+		"overnight shift": {
+			repr:     ",,,9h-12h+22h-6h,7h-12h,,",
+			expected: WeeklySchedule{{}, {}, {}, {{time.Hour * 9, time.Hour * 12}, {time.Hour * 22, time.Hour * 30}}, {{time.Hour * 7, time.Hour * 12}}, {}, {}},
+		},
+		"overnight shift not last": {
+			repr:               ",,,22h-6h+23h-23h30m,,,",
+			expectErrorMessage: "Wednesday's shifts are not correctly sorted",
+		},
+		"overnight shift overlaps next day": {
+			repr:               ",,,22h-6h,5h-12h,,",
+			expectErrorMessage: "Wednesday's last shift overlaps with Thursday's first shift",
+		},
+		"overnight shift overlaps next week": {
+			repr:               "5h-12h,,,,,,22h-6h",
+			expectErrorMessage: "Saturday's last shift overlaps with Sunday's first shift",
+		},
+		"start over 24h": {
+			repr:               ",,,25h-26h,,,",
+			expectErrorMessage: "start must be less to 24h",
 		},
 		"shift overlap": {
 			repr:               ",,,9h-12h+10h-13h,,,",
@@ -137,6 +165,18 @@ func Test_WeeklySchedule_Inverted(t *testing.T) {
 				{{0, (24 * time.Hour) - 1}},
 			},
 		},
+		"overnight": {
+			ws: getNightWorkhoursSchedule(),
+			expected: WeeklySchedule{
+				{{0, (24 * time.Hour) - 1}},
+				{{0, 22 * time.Hour}},
+				{{6 * time.Hour, 22 * time.Hour}},
+				{{6 * time.Hour, 22 * time.Hour}},
+				{{6 * time.Hour, 22 * time.Hour}},
+				{{6 * time.Hour, 22 * time.Hour}},
+				{{6 * time.Hour, (24 * time.Hour) - 1}},
+			},
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			test.Assert(check.Compare(t, tc.ws.Inverted(), tc.expected))
@@ -194,6 +234,27 @@ func Test_WeeklySchedule_CurrentShift(t *testing.T) {
 			time:        time.Date(2024, time.July, 8, 7, 9, 52, 29, time.UTC),
 			expectShift: nil,
 		},
+		"monday night on night schedule": {
+			ws:   getNightWorkhoursSchedule(),
+			time: time.Date(2024, time.July, 8, 23, 0, 0, 0, time.UTC),
+			expectShift: &WorkingShift{
+				time.Date(2024, time.July, 8, 22, 0, 0, 0, time.UTC),
+				time.Date(2024, time.July, 9, 6, 0, 0, 0, time.UTC),
+			},
+		},
+		"saturday early-morning on night schedule": {
+			ws:   getNightWorkhoursSchedule(),
+			time: time.Date(2024, time.July, 13, 3, 0, 0, 0, time.UTC),
+			expectShift: &WorkingShift{
+				time.Date(2024, time.July, 12, 22, 0, 0, 0, time.UTC),
+				time.Date(2024, time.July, 13, 6, 0, 0, 0, time.UTC),
+			},
+		},
+		"monday early-morning on night schedule": {
+			ws:          getNightWorkhoursSchedule(),
+			time:        time.Date(2024, time.July, 8, 3, 0, 0, 0, time.UTC),
+			expectShift: nil,
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			shift := tc.ws.CurrentShift(tc.time)
@@ -264,6 +325,22 @@ func Test_WeeklySchedule_PreviousShift(t *testing.T) {
 				time.Date(2024, time.July, 5, 19, 0, 0, 0, time.UTC),
 			},
 		},
+		"tuesday afternoon on night schedule": {
+			ws:   getNightWorkhoursSchedule(),
+			time: time.Date(2024, time.July, 9, 14, 0, 0, 0, time.UTC),
+			expectShift: &WorkingShift{
+				time.Date(2024, time.July, 8, 22, 0, 0, 0, time.UTC),
+				time.Date(2024, time.July, 9, 6, 0, 0, 0, time.UTC),
+			},
+		},
+		"tuesday early-morning on night schedule": {
+			ws:   getNightWorkhoursSchedule(),
+			time: time.Date(2024, time.July, 9, 3, 0, 0, 0, time.UTC),
+			expectShift: &WorkingShift{
+				time.Date(2024, time.July, 5, 22, 0, 0, 0, time.UTC),
+				time.Date(2024, time.July, 6, 6, 0, 0, 0, time.UTC),
+			},
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			shift := tc.ws.PreviousShift(tc.time)
@@ -326,6 +403,14 @@ func Test_WeeklySchedule_NextShift(t *testing.T) {
 				time.Date(2024, time.July, 8, 12, 0, 0, 0, time.UTC),
 			},
 		},
+		"saturday early-morning on night schedule": {
+			ws:   getNightWorkhoursSchedule(),
+			time: time.Date(2024, time.July, 13, 3, 0, 0, 0, time.UTC),
+			expectShift: &WorkingShift{
+				time.Date(2024, time.July, 15, 22, 0, 0, 0, time.UTC),
+				time.Date(2024, time.July, 16, 6, 0, 0, 0, time.UTC),
+			},
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			shift := tc.ws.NextShift(tc.time)
@@ -338,6 +423,39 @@ func Test_WeeklySchedule_NextShift(t *testing.T) {
 	}
 }
 
+func Test_WeeklySchedule_String(t *testing.T) {
+	for name, tc := range map[string]struct {
+		ws       WeeklySchedule
+		expected string
+	}{
+		"empty":     {ws: WeeklySchedule{}, expected: ",,,,,,"},
+		"regular":   {ws: getRegularWorkhoursSchedule(), expected: ",8h-18h,8h-18h,8h-18h,8h-18h,8h-18h,"},
+		"custom":    {ws: getCustomWorkhoursSchedule(), expected: ",8h-12h+14h-19h,8h-12h+14h-19h,8h-13h,8h-12h+14h-19h,8h-12h+14h-19h,"},
+		"overnight": {ws: getNightWorkhoursSchedule(), expected: ",22h-6h,22h-6h,22h-6h,22h-6h,22h-6h,"},
+		"inverted":  {ws: getNightWorkhoursSchedule().Inverted(), expected: "0s-23h59m59.999999999s,0s-22h,6h-22h,6h-22h,6h-22h,6h-22h,6h-23h59m59.999999999s"},
+		"minutes": {
+			ws:       WeeklySchedule{{{9*time.Hour + 30*time.Minute, 12*time.Hour + 45*time.Second}}, {{5 * time.Minute, time.Hour}}, {}, {}, {}, {}, {}},
+			expected: "9h30m-12h0m45s,5m-1h,,,,,",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			test.Assert(check.Compare(t, tc.ws.String(), tc.expected))
+
+			if name != "empty" {
+				parsed, err := ParseWeeklySchedule(tc.ws.String())
+				test.Require(t, err == nil, err)
+				test.Assert(check.Compare(t, parsed, tc.ws))
+			}
+		})
+	}
+}
+
+func Test_WorkingShiftSchedule_MarshalText(t *testing.T) {
+	raw, err := WorkingShiftSchedule{22 * time.Hour, 30 * time.Hour}.MarshalText()
+	test.Require(t, err == nil, err)
+	test.Assert(check.Compare(t, string(raw), "22h-6h"))
+}
+
 func Test_WorkingShift_String(t *testing.T) {
 	var ws *WorkingShift
 	test.Assert(t, ws.String() == "undefined shift")